@@ -0,0 +1,269 @@
+// Package bnf lets to define lr0 grammar with yacc-like text:
+//
+//	Goal : Sum ;
+//	Sum  : Sum "+" Prod | Sum "-" Prod | Prod ;
+//	Prod : Prod "*" Val | Prod "/" Val | Val ;
+//	Val  : int | "(" Sum ")" ;
+//	// comment till the end of line
+//
+// The first rule is main, so it must have the only alternative. An empty
+// alternative is allowed. Quoted literals are hidden terminals matching the
+// literal. Identifiers without rules are terminals which must be bound with
+// Grammar.Terminal. Evaluation is bound to rules with Grammar.Do.
+//
+//	g, err := bnf.Parse([]byte(src))
+//	...
+//	g.Terminal("int", func(t *lr0.TerminalFactory) lr0.Terminal {
+//		return t.FuncByte(isDigit, bytesToInt)
+//	})
+//	g.Do(`Sum : Sum "+" Prod`, func(a, b int) int { return a + b })
+//	...
+//	terminals, rules, err := g.Definition()
+//	...
+//	parser := lr0.New(
+//		append(terminals, lr0.NewWhitespace().FuncRune(unicode.IsSpace)),
+//		rules,
+//		lr0.WithMode(lr0.LALR1),
+//	)
+package bnf
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/vovan-ve/go-lr0-parser"
+)
+
+// Parse parses grammar definition text
+//
+// Symbols get Id in order of appearance, see Grammar.Id.
+func Parse(src []byte) (*Grammar, error) {
+	v, err := parser.Parse(lr0.NewState(src))
+	if err != nil {
+		return nil, err
+	}
+	g := &Grammar{ids: make(map[string]lr0.Id)}
+	for _, rd := range v.([]ruleDef) {
+		nt := g.nonTerminal(rd.name)
+		for _, alt := range rd.alts {
+			nt.alts = append(nt.alts, g.alternative(alt))
+			nt.handlers = append(nt.handlers, nil)
+		}
+	}
+	for _, t := range g.seen {
+		if _, ok := g.nonTerms[t.id]; ok {
+			continue
+		}
+		g.terms = append(g.terms, t)
+	}
+	return g, nil
+}
+
+// Grammar is parsed grammar definition to bind terminals matching and rules
+// evaluation to
+//
+// Binding mistakes are reported by Definition.
+type Grammar struct {
+	ids      map[string]lr0.Id
+	names    []string
+	nonTerms map[lr0.Id]*nonTerminal
+	ntOrder  []*nonTerminal
+	// seen are symbols first seen in alternatives in order of appearance,
+	// they are terminals unless they have rules
+	seen  []*terminal
+	terms []*terminal
+	errs  []error
+}
+
+type terminal struct {
+	id      lr0.Id
+	name    string
+	literal string
+	isLit   bool
+	t       lr0.Terminal
+}
+
+type nonTerminal struct {
+	id       lr0.Id
+	name     string
+	alts     [][]lr0.Id
+	handlers []any
+}
+
+// Id returns Id of the symbol with the given name, or lr0.InvalidId if there
+// is no such symbol. Name of literal is quoted:
+//
+//	g.Id(`"+"`)
+func (g *Grammar) Id(name string) lr0.Id { return g.ids[name] }
+
+// Terminal binds a Terminal to the terminal with the given name. The given
+// func receives TerminalFactory with Id and name of the terminal.
+//
+//	g.Terminal("int", func(t *lr0.TerminalFactory) lr0.Terminal {
+//		return t.FuncByte(isDigit, bytesToInt)
+//	})
+func (g *Grammar) Terminal(name string, fn func(t *lr0.TerminalFactory) lr0.Terminal) *Grammar {
+	for _, t := range g.terms {
+		if t.name == name && !t.isLit {
+			t.t = fn(lr0.NewTerm(t.id, t.name))
+			return g
+		}
+	}
+	g.errs = append(g.errs, errors.Wrapf(lr0.ErrDefine, "no terminal %s to bind", name))
+	return g
+}
+
+// Do binds evaluation handler to the given rule alternative, see
+// lr0.NonTerminal.Do. The rule is written in the same syntax, but with
+// single alternative and without `;`:
+//
+//	g.Do(`Sum : Sum "+" Prod`, func(a, b int) int { return a + b })
+//	g.Do(`Sign :`, func() int { return 1 })
+func (g *Grammar) Do(rule string, handler any) *Grammar {
+	v, err := parser.Parse(lr0.NewState([]byte(rule + ";")))
+	if err != nil {
+		g.errs = append(g.errs, errors.Wrapf(lr0.ErrDefine, "invalid rule %q: %v", rule, err))
+		return g
+	}
+	rd := v.([]ruleDef)
+	if len(rd) != 1 || len(rd[0].alts) != 1 {
+		g.errs = append(g.errs, errors.Wrapf(lr0.ErrDefine, "invalid rule %q: the only alternative is expected", rule))
+		return g
+	}
+	if nt, ok := g.nonTerms[g.ids[rd[0].name]]; ok {
+	Alts:
+		for i, alt := range nt.alts {
+			if len(alt) != len(rd[0].alts[0]) {
+				continue
+			}
+			for j, s := range rd[0].alts[0] {
+				if g.ids[s.name] != alt[j] {
+					continue Alts
+				}
+			}
+			nt.handlers[i] = handler
+			return g
+		}
+	}
+	g.errs = append(g.errs, errors.Wrapf(lr0.ErrDefine, "no rule %q to bind", rule))
+	return g
+}
+
+// Definition returns terminals and rules to use with lr0.New. Literals are
+// first from longer to shorter, so a literal will not match a part of
+// another one.
+//
+// Binding mistakes and unbound terminals are returned as
+// lr0.DefinitionErrors or a single error.
+func (g *Grammar) Definition() ([]lr0.Terminal, []lr0.NonTerminalDefinition, error) {
+	errs := append([]error(nil), g.errs...)
+
+	var lits, named []lr0.Terminal
+	sorted := append([]*terminal(nil), g.terms...)
+	sort.SliceStable(sorted, func(i, j int) bool { return len(sorted[i].literal) > len(sorted[j].literal) })
+	for _, t := range sorted {
+		switch {
+		case t.isLit && t.literal == "":
+			errs = append(errs, errors.Wrapf(lr0.ErrDefine, "terminal %s is empty", t.name))
+		case t.isLit:
+			lits = append(lits, lr0.NewTerm(t.id, t.name).Hide().Str(t.literal))
+		case t.t != nil:
+			named = append(named, t.t)
+		default:
+			errs = append(errs, errors.Wrapf(lr0.ErrDefine, "terminal %s is not bound", t.name))
+		}
+	}
+
+	rules := make([]lr0.NonTerminalDefinition, 0, len(g.ntOrder))
+	for i, nt := range g.ntOrder {
+		def := lr0.NewNT(nt.id, nt.name)
+		if i == 0 {
+			// chainable API would panic
+			if len(nt.alts) != 1 {
+				errs = append(errs, errors.Wrapf(lr0.ErrDefine, "main rule %s must have the only alternative, here are %d", nt.name, len(nt.alts)))
+				continue
+			}
+			def.Main()
+		}
+		for j, alt := range nt.alts {
+			if len(alt) == 0 {
+				def.IsEmpty()
+			} else {
+				def.Is(alt[0], alt[1:]...)
+			}
+			if nt.handlers[j] != nil {
+				def.Do(nt.handlers[j])
+			}
+		}
+		rules = append(rules, def)
+	}
+
+	switch len(errs) {
+	case 0:
+		return append(lits, named...), rules, nil
+	case 1:
+		return nil, nil, errs[0]
+	default:
+		return nil, nil, lr0.DefinitionErrors(errs)
+	}
+}
+
+func (g *Grammar) id(name string) (lr0.Id, bool) {
+	if id, ok := g.ids[name]; ok {
+		return id, false
+	}
+	g.names = append(g.names, name)
+	id := lr0.Id(len(g.names))
+	g.ids[name] = id
+	return id, true
+}
+
+func (g *Grammar) nonTerminal(name string) *nonTerminal {
+	id, _ := g.id(name)
+	if g.nonTerms == nil {
+		g.nonTerms = make(map[lr0.Id]*nonTerminal)
+	}
+	if nt, ok := g.nonTerms[id]; ok {
+		return nt
+	}
+	nt := &nonTerminal{id: id, name: name}
+	g.nonTerms[id] = nt
+	g.ntOrder = append(g.ntOrder, nt)
+	return nt
+}
+
+func (g *Grammar) alternative(alt []symbol) []lr0.Id {
+	res := make([]lr0.Id, 0, len(alt))
+	for _, s := range alt {
+		id, isNew := g.id(s.name)
+		if isNew {
+			g.seen = append(g.seen, &terminal{
+				id:      id,
+				name:    s.name,
+				literal: s.literal,
+				isLit:   s.isLit,
+			})
+		}
+		res = append(res, id)
+	}
+	return res
+}
+
+// String renders the grammar back to text
+func (g *Grammar) String() string {
+	s := ""
+	for _, nt := range g.ntOrder {
+		s += nt.name + " :"
+		for i, alt := range nt.alts {
+			if i > 0 {
+				s += " |"
+			}
+			for _, id := range alt {
+				s += " " + g.names[id-1]
+			}
+		}
+		s += " ;\n"
+	}
+	return strings.TrimSuffix(s, "\n")
+}
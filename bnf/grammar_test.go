@@ -0,0 +1,105 @@
+package bnf_test
+
+import (
+	"strconv"
+	"testing"
+	"unicode"
+
+	"github.com/pkg/errors"
+	"github.com/vovan-ve/go-lr0-parser"
+	"github.com/vovan-ve/go-lr0-parser/bnf"
+)
+
+const calcSrc = `
+// calculator
+Goal : Sum ;
+Sum  : Sum "+" Prod
+     | Sum "-" Prod
+     | Prod ;
+Prod : Prod "*" Val | Val ;
+Val  : Sign int
+     | "(" Sum ")" ;
+Sign : "-" | ;
+`
+
+func TestParse(t *testing.T) {
+	g, err := bnf.Parse([]byte(calcSrc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	const expected = `Goal : Sum ;
+Sum : Sum "+" Prod | Sum "-" Prod | Prod ;
+Prod : Prod "*" Val | Val ;
+Val : Sign int | "(" Sum ")" ;
+Sign : "-" | ;`
+	if s := g.String(); s != expected {
+		t.Errorf("grammar:\n%s", s)
+	}
+	if g.Id("Goal") != 1 || g.Id("Sum") != 2 || g.Id(`"+"`) != 3 || g.Id("nothing") != lr0.InvalidId {
+		t.Error("wrong ids")
+	}
+
+	g.Terminal("int", func(t *lr0.TerminalFactory) lr0.Terminal {
+		return t.FuncByte(isDigit, bytesToInt)
+	}).
+		Do(`Sum : Sum "+" Prod`, func(a, b int) int { return a + b }).
+		Do(`Sum : Sum "-" Prod`, func(a, b int) int { return a - b }).
+		Do(`Prod : Prod "*" Val`, func(a, b int) int { return a * b }).
+		Do(`Val : Sign int`, func(s, v int) int { return s * v }).
+		Do(`Sign : "-"`, func() int { return -1 }).
+		Do(`Sign :`, func() int { return 1 })
+
+	terminals, rules, err := g.Definition()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := lr0.NewE(
+		append(terminals, lr0.NewWhitespace().FuncRune(unicode.IsSpace)),
+		rules,
+		lr0.WithMode(lr0.LALR1),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := p.Parse(lr0.NewState([]byte("2 * (3 + -4) - 5")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != -7 {
+		t.Errorf("result %v", v)
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	t.Run("syntax", func(t *testing.T) {
+		_, err := bnf.Parse([]byte(`Goal : Sum ; Sum : "+ ;`))
+		if !errors.Is(err, lr0.ErrParse) {
+			t.Fatal("unexpected error:", err)
+		}
+		_, err = bnf.Parse([]byte(`Goal : Sum Sum : x ;`))
+		if err == nil || err.Error() != `unexpected input: expected identifier, string, "|" or ";": parse error at 1:15 near ⟪Goal␠:␠Sum␠Sum⟫⏵⟪␠:␠x␠;⟫` {
+			t.Fatal("unexpected error:", err)
+		}
+	})
+
+	t.Run("binding", func(t *testing.T) {
+		g, err := bnf.Parse([]byte(`Goal : Sum ; Sum : Sum "+" int | int ;`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		g.Terminal("Sum", nil).
+			Do(`Sum : Sum "-" int`, nil).
+			Do(`Sum : int | Sum`, nil)
+		_, _, err = g.Definition()
+		const expected = `no terminal Sum to bind: invalid definition
+no rule "Sum : Sum \"-\" int" to bind: invalid definition
+invalid rule "Sum : int | Sum": the only alternative is expected: invalid definition
+terminal int is not bound: invalid definition`
+		if !errors.Is(err, lr0.ErrDefine) || err.Error() != expected {
+			t.Fatal("unexpected error:", err)
+		}
+	})
+}
+
+func isDigit(b byte) bool              { return b >= '0' && b <= '9' }
+func bytesToInt(b []byte) (int, error) { return strconv.Atoi(string(b)) }
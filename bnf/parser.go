@@ -0,0 +1,132 @@
+package bnf
+
+import (
+	"strconv"
+	"unicode"
+
+	"github.com/vovan-ve/go-lr0-parser"
+)
+
+const (
+	tIdent lr0.Id = iota + 1
+	tString
+	tColon
+	tPipe
+	tSemicolon
+
+	nItem
+	nSeq
+	nAlts
+	nRule
+	nRules
+	nGoal
+)
+
+// symbol is an item of a rule alternative as written
+type symbol struct {
+	// name is an identifier or quoted literal
+	name string
+	// literal is unquoted value of literal
+	literal string
+	isLit   bool
+}
+
+type ruleDef struct {
+	name string
+	alts [][]symbol
+}
+
+var parser = lr0.New(
+	[]lr0.Terminal{
+		lr0.NewTerm(tIdent, "identifier").Func(matchIdent),
+		lr0.NewTerm(tString, "string").Func(matchString),
+		lr0.NewTerm(tColon, `":"`).Hide().Str(":"),
+		lr0.NewTerm(tPipe, `"|"`).Hide().Str("|"),
+		lr0.NewTerm(tSemicolon, `";"`).Hide().Str(";"),
+
+		lr0.NewWhitespace().FuncRune(unicode.IsSpace),
+		lr0.NewWhitespace().Func(matchComment),
+	},
+	[]lr0.NonTerminalDefinition{
+		lr0.NewNT(nGoal, "Goal").Main().Is(nRules),
+		lr0.NewNT(nRules, "Rules").
+			Is(nRules, nRule).Do(func(list []ruleDef, r ruleDef) []ruleDef { return append(list, r) }).
+			Is(nRule).Do(func(r ruleDef) []ruleDef { return []ruleDef{r} }),
+		lr0.NewNT(nRule, "Rule").
+			Is(tIdent, tColon, nAlts, tSemicolon).Do(func(name string, alts [][]symbol) ruleDef {
+			return ruleDef{name: name, alts: alts}
+		}),
+		lr0.NewNT(nAlts, "Alternatives").
+			Is(nAlts, tPipe, nSeq).Do(func(list [][]symbol, s []symbol) [][]symbol { return append(list, s) }).
+			Is(nSeq).Do(func(s []symbol) [][]symbol { return [][]symbol{s} }),
+		lr0.NewNT(nSeq, "Sequence").
+			Is(nSeq, nItem).Do(func(list []symbol, s symbol) []symbol { return append(list, s) }).
+			IsEmpty().Do(func() []symbol { return nil }),
+		lr0.NewNT(nItem, "Item").
+			Is(tIdent).Do(func(name string) symbol { return symbol{name: name} }).
+			Is(tString).Do(func(lit string) symbol {
+			return symbol{name: strconv.Quote(lit), literal: lit, isLit: true}
+		}),
+	},
+	lr0.WithMode(lr0.LALR1),
+)
+
+func matchIdent(state *lr0.State) (next *lr0.State, value any) {
+	if state.IsEOF() {
+		return
+	}
+	if next, _ = state.TakeByteFunc(isAlpha); next == nil {
+		return
+	}
+	next, _ = next.TakeBytesFunc(isAlphaNum)
+	value = string(state.BytesTo(next))
+	return
+}
+
+func matchString(state *lr0.State) (next *lr0.State, value any) {
+	next, ok := state.ExpectByteOk('"')
+	if !ok {
+		return nil, nil
+	}
+	for escaped := false; ; {
+		if next.IsEOF() {
+			return nil, lr0.WithSource(lr0.NewParseError("unterminated string"), state)
+		}
+		var b byte
+		next, b = next.TakeByte()
+		switch {
+		case escaped:
+			escaped = false
+		case b == '\\':
+			escaped = true
+		case b == '\n':
+			return nil, lr0.WithSource(lr0.NewParseError("newline in string"), state)
+		case b == '"':
+			s, err := strconv.Unquote(string(state.BytesTo(next)))
+			if err != nil {
+				return nil, lr0.WithSource(lr0.NewParseError("invalid string"), state)
+			}
+			return next, s
+		}
+	}
+}
+
+func matchComment(state *lr0.State) (next *lr0.State, value any) {
+	next, ok := state.ExpectByteOk('/', '/')
+	if !ok {
+		return nil, nil
+	}
+	next, _ = next.TakeBytesFunc(func(b byte) bool { return b != '\n' })
+	return
+}
+
+func isAlpha(b byte) bool {
+	switch {
+	case b >= 'A' && b <= 'Z', b >= 'a' && b <= 'z', b == '_':
+		return true
+	default:
+		return false
+	}
+}
+
+func isAlphaNum(b byte) bool { return isAlpha(b) || (b >= '0' && b <= '9') }
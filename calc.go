@@ -11,6 +11,15 @@ type calcFunc func([]any) (any, error)
 var typeOfError = reflect.TypeOf((*error)(nil)).Elem()
 
 func newCalcFunc(fn any, expectArgsCount int) calcFunc {
+	if h, ok := fn.(Handler); ok {
+		if h.calc == nil {
+			return newCalcFunc(nil, expectArgsCount)
+		}
+		if h.args != expectArgsCount {
+			panic(errors.Wrapf(ErrDefine, "fn arguments count is %d when wanted %d", h.args, expectArgsCount))
+		}
+		return h.calc
+	}
 	if fn == nil && expectArgsCount == 1 {
 		return calcDefaultBubble
 	}
@@ -34,7 +43,7 @@ func newCalcFunc(fn any, expectArgsCount int) calcFunc {
 	switch funcT.NumOut() {
 	case 1:
 		return func(v []any) (any, error) {
-			res := funcV.Call(prepareCalcArgs(v))
+			res := funcV.Call(prepareCalcArgs(funcT, v))
 			return res[0].Interface(), nil
 		}
 	case 2:
@@ -42,7 +51,7 @@ func newCalcFunc(fn any, expectArgsCount int) calcFunc {
 			panic(errors.Wrapf(ErrDefine, "fn func 2nd result must be `error`, given %v", t1))
 		}
 		return func(v []any) (any, error) {
-			res := funcV.Call(prepareCalcArgs(v))
+			res := funcV.Call(prepareCalcArgs(funcT, v))
 			v0 := res[0].Interface()
 			v1 := res[1].Interface()
 			if v1 == nil {
@@ -55,12 +64,87 @@ func newCalcFunc(fn any, expectArgsCount int) calcFunc {
 	}
 }
 
-func prepareCalcArgs(vs []any) []reflect.Value {
+// calcTypes returns types of arguments and result of the given valid Do
+// handler. Types are nil for nil handler.
+func calcTypes(fn any) (in []reflect.Type, out reflect.Type) {
+	if h, ok := fn.(Handler); ok {
+		return h.in, h.out
+	}
+	if fn == nil {
+		return nil, nil
+	}
+	funcT := reflect.TypeOf(fn)
+	for i := 0; i < funcT.NumIn(); i++ {
+		in = append(in, funcT.In(i))
+	}
+	return in, funcT.Out(0)
+}
+
+func prepareCalcArgs(funcT reflect.Type, vs []any) []reflect.Value {
 	res := make([]reflect.Value, 0, len(vs))
-	for _, v := range vs {
-		res = append(res, reflect.ValueOf(v))
+	for i, v := range vs {
+		res = append(res, calcArg(v, funcT.In(i)))
 	}
 	return res
 }
 
+var typeOfAnySlice = reflect.TypeOf([]any(nil))
+
+// calcArg converts the value for an argument of the given type. The nil
+// becomes zero value, and `[]any` from ZeroOrMore and similar symbols becomes
+// a slice of the argument type.
+func calcArg(v any, t reflect.Type) reflect.Value {
+	if v == nil {
+		return reflect.Zero(t)
+	}
+	if list, ok := v.([]any); ok {
+		if res, ok := convertList(list, t); ok {
+			return res
+		}
+	}
+	return reflect.ValueOf(v)
+}
+
+// convertList converts `[]any` from ZeroOrMore and similar symbols to a
+// slice of the given type. Returns false if it's impossible.
+func convertList(list []any, t reflect.Type) (reflect.Value, bool) {
+	if t.Kind() != reflect.Slice || t == typeOfAnySlice {
+		return reflect.Value{}, false
+	}
+	elemT := t.Elem()
+	res := reflect.MakeSlice(t, len(list), len(list))
+	for i, item := range list {
+		switch it := item.(type) {
+		case nil:
+			continue
+		case []any:
+			v, ok := convertList(it, elemT)
+			if !ok {
+				if !typeOfAnySlice.AssignableTo(elemT) {
+					return reflect.Value{}, false
+				}
+				v = reflect.ValueOf(it)
+			}
+			res.Index(i).Set(v)
+		default:
+			v := reflect.ValueOf(it)
+			if !v.Type().AssignableTo(elemT) {
+				return reflect.Value{}, false
+			}
+			res.Index(i).Set(v)
+		}
+	}
+	return res, true
+}
+
 func calcDefaultBubble(v []any) (any, error) { return v[0], nil }
+
+// isNilHandler checks if fn is nil or empty Handler, which means no Do
+func isNilHandler(fn any) bool {
+	if h, ok := fn.(Handler); ok {
+		return h.calc == nil
+	}
+	return fn == nil
+}
+
+func calcDefaultNil([]any) (any, error) { return nil, nil }
@@ -56,6 +56,10 @@ func TestNewCalcFunc(t *testing.T) {
 		defer testutils.ExpectPanicError(t, ErrDefine)
 		newCalcFunc(42, 0)
 	})
+	t.Run("panic: nil with args", func(t *testing.T) {
+		defer testutils.ExpectPanicError(t, ErrDefine)
+		newCalcFunc(nil, 2)
+	})
 	t.Run("panic: null func", func(t *testing.T) {
 		defer testutils.ExpectPanicError(t, ErrDefine)
 		var fn func(any)
@@ -0,0 +1,165 @@
+// Command lr0gen generates Go source file with a prebuilt parsing table for
+// a grammar, so a Parser will not build the table at run-time.
+//
+// The grammar must be exposed by a func in a non-main package:
+//
+//	//go:generate go run github.com/vovan-ve/go-lr0-parser/cmd/lr0gen -var Table
+//
+//	func Grammar() ([]lr0.Terminal, []lr0.NonTerminalDefinition, []lr0.Option) {
+//		...
+//	}
+//
+// The generated file declares a variable of type *lr0.TableData in the same
+// package to use with lr0.WithTable in another package:
+//
+//	terminals, rules, options := calc.Grammar()
+//	parser := lr0.New(terminals, rules, append(options, lr0.WithTable(calc.Table))...)
+//
+// The package must compile before the first generation, so start with a stub
+// file declaring `var Table *lr0.TableData`. Since the package is imported to
+// generate the table, it must not create the Parser with the table on
+// initialization, because the table is outdated while the grammar changes.
+// See examples/03-calc-static.
+//
+// Usage:
+//
+//	lr0gen [flags] [package dir]
+//
+// Package dir is the current directory by default.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+func main() {
+	var (
+		funcName = flag.String("func", "Grammar", "func in the package which returns grammar definition")
+		varName  = flag.String("var", "parserTable", "variable name to declare in output file")
+		output   = flag.String("o", "table_lr0.go", "output file, relative to package dir")
+		pkgName  = flag.String("pkg", "", "package name of output file, package of the grammar by default")
+	)
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "Usage: lr0gen [flags] [package dir]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	dir := "."
+	switch flag.NArg() {
+	case 0:
+	case 1:
+		dir = flag.Arg(0)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(dir, *funcName, *varName, *output, *pkgName); err != nil {
+		fmt.Fprintln(os.Stderr, "lr0gen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(dir, funcName, varName, output, pkgName string) error {
+	importPath, name, err := listPackage(dir)
+	if err != nil {
+		return err
+	}
+	if name == "main" {
+		return fmt.Errorf("package %s is main, the grammar must be in importable package", importPath)
+	}
+	if pkgName == "" {
+		pkgName = name
+	}
+
+	// temporary program must be inside the module to import the package
+	tmp, err := os.MkdirTemp(dir, "_lr0gen")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	src := &bytes.Buffer{}
+	err = programTemplate.Execute(src, map[string]string{
+		"ImportPath": importPath,
+		"Func":       funcName,
+		"Package":    pkgName,
+		"Var":        varName,
+	})
+	if err != nil {
+		return err
+	}
+	// the program runs in package dir, which may differ from current one
+	mainFile, err := filepath.Abs(filepath.Join(tmp, "main.go"))
+	if err != nil {
+		return err
+	}
+	if err = os.WriteFile(mainFile, src.Bytes(), 0o644); err != nil {
+		return err
+	}
+
+	stdout := &bytes.Buffer{}
+	cmd := exec.Command("go", "run", mainFile)
+	cmd.Dir = dir
+	cmd.Stdout = stdout
+	cmd.Stderr = os.Stderr
+	if err = cmd.Run(); err != nil {
+		return fmt.Errorf("cannot build table: %w", err)
+	}
+
+	if !filepath.IsAbs(output) {
+		output = filepath.Join(dir, output)
+	}
+	return os.WriteFile(output, stdout.Bytes(), 0o644)
+}
+
+// listPackage returns import path and name of the package in the given dir
+func listPackage(dir string) (importPath, name string, err error) {
+	cmd := exec.Command("go", "list", "-f", "{{.ImportPath}} {{.Name}}", ".")
+	cmd.Dir = dir
+	cmd.Stderr = os.Stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", "", fmt.Errorf("cannot list package: %w", err)
+	}
+	f := strings.Fields(string(out))
+	if len(f) != 2 {
+		return "", "", fmt.Errorf("unexpected go list output: %q", out)
+	}
+	return f[0], f[1], nil
+}
+
+var programTemplate = template.Must(template.New("main").Parse(`// Code generated by lr0gen. DO NOT EDIT.
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/vovan-ve/go-lr0-parser"
+
+	grammar "{{.ImportPath}}"
+)
+
+func main() {
+	terminals, rules, options := grammar.{{.Func}}()
+	p, err := lr0.NewE(terminals, rules, options...)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if err = lr0.WriteGo(os.Stdout, p, "{{.Package}}", "{{.Var}}"); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+`))
@@ -54,6 +54,7 @@ func isAlpha(b byte) bool {
 func bytesToInt(b []byte) (int, error) { return strconv.Atoi(string(b)) }
 
 func calc3AnyNil(any, any, any) (any, error)     { return nil, nil }
+func calc2AnyFirst(a, _ any) any                 { return a }
 func calc3StrTrace(a, op, b string) (any, error) { return "(" + a + " " + op + " " + b + ")", nil }
 func calc2IntSum(a, b int) int                   { return a + b }
 func calc2IntSub(a, b int) int                   { return a - b }
@@ -0,0 +1,206 @@
+package lr0
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ConflictError describes a conflict found in a state while building the table
+//
+// It wraps either ErrConflictShiftReduce or ErrConflictReduceReduce:
+//
+//	errors.Is(err, ErrConflictShiftReduce)
+//
+//	var ce *ConflictError
+//	if errors.As(err, &ce) {
+//		fmt.Println(ce.State, ce.Items)
+//	}
+type ConflictError struct {
+	err error
+	reg SymbolRegistry
+	// State is index of the state in the table
+	State int
+	// Items are items of the state rendered with `>` pointing to the current
+	// position, for example `Sum : Sum > "+" Val`. Final items have their
+	// lookahead terminals in square brackets in lookahead table modes.
+	Items []string
+	// Rules are conflicting rules. Reduce rules go first, and then rules of
+	// items to shift.
+	Rules []Rule
+	// Lookahead are terminals on which the conflict happens. It can contain
+	// EOF. It is empty for reduce-reduce conflict in LR0 mode, which means
+	// any input.
+	Lookahead []Id
+	// Prefix is an example input leading to the state from the initial
+	// state. It's the shortest sequence of symbols leading to the state with
+	// non-terminals expanded to their shortest sequences of terminals.
+	// Non-terminals which cannot be expanded, since they are non-productive,
+	// stay as is.
+	Prefix []Id
+}
+
+func (e *ConflictError) Error() string {
+	s := "state " + strconv.Itoa(e.State) + " on "
+	if len(e.Lookahead) == 0 {
+		s += "any input"
+	} else {
+		for i, id := range e.Lookahead {
+			if i > 0 {
+				s += ", "
+			}
+			s += dumpId(id, e.reg)
+		}
+	}
+	s += " after " + e.PrefixString() + ": " + e.err.Error() + "\n"
+	for _, it := range e.Items {
+		s += "- " + it + "\n"
+	}
+	s += "rules:"
+	for _, r := range e.Rules {
+		s += "\n- " + r.String()
+	}
+	return s
+}
+
+func (e *ConflictError) Unwrap() error { return e.err }
+
+// PrefixString returns Prefix rendered with symbols names
+func (e *ConflictError) PrefixString() string {
+	if len(e.Prefix) == 0 {
+		return "start"
+	}
+	names := make([]string, 0, len(e.Prefix))
+	for _, id := range e.Prefix {
+		names = append(names, dumpId(id, e.reg))
+	}
+	return strings.Join(names, " ")
+}
+
+// conflict collects details about one kind of conflict in a state
+type conflict struct {
+	err       error
+	rules     []Rule
+	lookahead idSet
+}
+
+func newConflict(err error) *conflict {
+	return &conflict{err: err, lookahead: newIdSet()}
+}
+
+func (c *conflict) add(id Id, rules ...Rule) {
+	if id != InvalidId {
+		c.lookahead.Add(id)
+	}
+AddRules:
+	for _, r := range rules {
+		for _, have := range c.rules {
+			if have == r {
+				continue AddRules
+			}
+		}
+		c.rules = append(c.rules, r)
+	}
+}
+
+func (c *conflict) isEmpty() bool { return len(c.rules) == 0 }
+
+// newConflictError creates ConflictError for the given state
+//
+// `lookahead` is optional to render lookahead of final items.
+func newConflictError(c *conflict, si tableStateIndex, st tableItemset, prefix []Id, g *grammar, lookahead func(tableItem) readonlyIdSet) *ConflictError {
+	items := make([]string, 0, len(st.items))
+	for _, it := range st.items {
+		s := it.dump(g)
+		if lookahead != nil && !it.HasFurther() && !it.HasEOF() {
+			s += " [" + dumpIds(lookahead(it).Ids(), g) + "]"
+		}
+		items = append(items, s)
+	}
+	return &ConflictError{
+		err:       c.err,
+		reg:       g,
+		State:     si,
+		Items:     items,
+		Rules:     c.rules,
+		Lookahead: c.lookahead.Ids(),
+		Prefix:    prefix,
+	}
+}
+
+// shortestSentences finds the shortest sequence of terminals for every
+// productive non-terminal. Rules go in definition order, so the first
+// shortest rule wins.
+func shortestSentences(g *grammar) map[Id][]Id {
+	res := make(map[Id][]Id)
+	expand := func(ids []Id) ([]Id, bool) {
+		s := []Id{}
+		for _, id := range ids {
+			if g.IsTerminal(id) {
+				s = append(s, id)
+				continue
+			}
+			sub, ok := res[id]
+			if !ok {
+				return nil, false
+			}
+			s = append(s, sub...)
+		}
+		return s, true
+	}
+	for changed := true; changed; {
+		changed = false
+		for _, r := range g.rules {
+			s, ok := expand(r.Definition())
+			if !ok {
+				continue
+			}
+			if prev, ok := res[r.Subject()]; !ok || len(s) < len(prev) {
+				res[r.Subject()] = s
+				changed = true
+			}
+		}
+	}
+	return res
+}
+
+// expandPrefix replaces non-terminals in the prefix with their shortest
+// sentences
+func expandPrefix(prefix []Id, sentences map[Id][]Id) []Id {
+	res := make([]Id, 0, len(prefix))
+	for _, id := range prefix {
+		if s, ok := sentences[id]; ok {
+			res = append(res, s...)
+		} else {
+			res = append(res, id)
+		}
+	}
+	return res
+}
+
+// shortestPrefixes finds the shortest sequence of symbols leading to every
+// state from `starts` initial states by breadth-first search over rows actions
+func shortestPrefixes(rows []*tableRow, starts int) [][]Id {
+	res := make([][]Id, len(rows))
+	seen := make([]bool, len(rows))
+	var queue []tableStateIndex
+	for si := 0; si < starts && si < len(rows); si++ {
+		seen[si] = true
+		queue = append(queue, si)
+	}
+	for len(queue) != 0 {
+		si := queue[0]
+		queue = queue[1:]
+		row := rows[si]
+		for _, actions := range [][]Action{row.data.Shift, row.data.Goto} {
+			for _, a := range actions {
+				if seen[a.To] {
+					continue
+				}
+				seen[a.To] = true
+				res[a.To] = append(append(make([]Id, 0, len(res[si])+1), res[si]...), a.Id)
+				queue = append(queue, a.To)
+			}
+		}
+	}
+	return res
+}
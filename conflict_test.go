@@ -0,0 +1,116 @@
+package lr0
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/vovan-ve/go-lr0-parser/internal/testutils"
+)
+
+func TestConflictError(t *testing.T) {
+	// Goal : S $
+	// S    : Val zero | Sum one | S plus S
+	// Val  : plus
+	// Sum  : plus
+	g := newGrammar(
+		[]Terminal{
+			NewTerm(tZero, "zero").Str("0"),
+			NewTerm(tOne, "one").Str("1"),
+			NewTerm(tPlus, `"+"`).Str("+"),
+		},
+		[]NonTerminalDefinition{
+			NewNT(nGoal, "Goal").Main().Is(nProd),
+			NewNT(nProd, "S").
+				Is(nVal, tZero).Do(calc2AnyFirst).
+				Is(nSum, tOne).Do(calc2AnyFirst).
+				Is(nProd, tPlus, nProd).Do(calc3AnyNil),
+			NewNT(nVal, "Val").Is(tPlus),
+			NewNT(nSum, "Sum").Is(tPlus),
+		},
+	)
+
+	t.Run("LR0 reduce-reduce", func(t *testing.T) {
+		defer testutils.ExpectPanicError(t, ErrConflictReduceReduce, func(t *testing.T, err error) {
+			var ce *ConflictError
+			if !errors.As(err, &ce) {
+				t.Fatalf("not ConflictError: %#v", err)
+			}
+			if ce.State != 1 {
+				t.Error("state", ce.State)
+			}
+			if fmt.Sprint(ce.Rules) != `[Val : "+" Sum : "+"]` {
+				t.Error("rules", ce.Rules)
+			}
+			if len(ce.Lookahead) != 0 {
+				t.Error("lookahead", ce.Lookahead)
+			}
+			const expected = `state 1 on any input after "+": reduce-reduce conflict: bad state for table: invalid definition
+- Val : "+" >
+- Sum : "+" >
+rules:
+- Val : "+"
+- Sum : "+"`
+			if err.Error() != expected {
+				t.Error("wrong error message:", err)
+			}
+		})
+		newTable(g)
+	})
+
+	t.Run("SLR1 shift-reduce", func(t *testing.T) {
+		defer testutils.ExpectPanicError(t, ErrConflictShiftReduce, func(t *testing.T, err error) {
+			var ce *ConflictError
+			if !errors.As(err, &ce) {
+				t.Fatalf("not ConflictError: %#v", err)
+			}
+			if fmt.Sprint(ce.Lookahead) != fmt.Sprint([]Id{tPlus}) {
+				t.Error("lookahead", ce.Lookahead)
+			}
+			if ce.PrefixString() != `"+" zero "+" "+" zero` {
+				t.Error("prefix", ce.PrefixString())
+			}
+			const expected = `state 8 on "+" after "+" zero "+" "+" zero: shift-reduce conflict: bad state for table: invalid definition
+- S : S "+" S > [$ "+"]
+- S : S > "+" S
+rules:
+- S : S "+" S`
+			if err.Error() != expected {
+				t.Error("wrong error message:", err)
+			}
+		})
+		newTable(g, WithMode(SLR1))
+	})
+
+	t.Run("LALR1 accept", func(t *testing.T) {
+		// Goal : S $
+		// S    : S X | zero
+		// X    : ε
+		defer testutils.ExpectPanicError(t, ErrConflictShiftReduce, func(t *testing.T, err error) {
+			var ce *ConflictError
+			if !errors.As(err, &ce) {
+				t.Fatalf("not ConflictError: %#v", err)
+			}
+			const expected = `state 2 on $ after zero: shift-reduce conflict: bad state for table: invalid definition
+- Goal : S > $
+- S : S > X
+- X : > [$]
+rules:
+- X : ε
+- Goal : S $`
+			if err.Error() != expected {
+				t.Error("wrong error message:", err)
+			}
+		})
+		newTable(newGrammar(
+			[]Terminal{
+				NewTerm(tZero, "zero").Str("0"),
+			},
+			[]NonTerminalDefinition{
+				NewNT(nGoal, "Goal").Main().Is(nSum),
+				NewNT(nSum, "S").Is(nSum, nVal).Do(calc2AnyFirst).Is(tZero),
+				NewNT(nVal, "X").IsEmpty(),
+			},
+		), WithMode(LALR1))
+	})
+}
@@ -0,0 +1,67 @@
+package lr0
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteDOT writes the LR automaton of the Parser in Graphviz DOT format
+//
+// Every state is labelled with its items from Table.Items. Edges are labelled
+// with symbols names: shifts of terminals are solid, and gotos of
+// non-terminals are dashed. States which can reduce are filled, and the
+// accepting state has double border.
+//
+//	err := WriteDOT(os.Stdout, parser)
+//	...
+//	$ go run . | dot -Tsvg > automaton.svg
+func WriteDOT(w io.Writer, p Parser) error {
+	var (
+		g   = p.Grammar()
+		t   = p.Table()
+		out = &strings.Builder{}
+	)
+	out.WriteString("digraph LR {\n")
+	out.WriteString("\trankdir=LR;\n")
+	out.WriteString("\tnode [shape=box, fontname=monospace];\n")
+	out.WriteString("\tedge [fontname=monospace];\n")
+
+	for i := 0; i < t.RowsCount(); i++ {
+		row := t.Row(i)
+		label := fmt.Sprintf("%d\\l", i)
+		for _, it := range t.Items(i) {
+			label += dotEscape(it) + "\\l"
+		}
+		attrs := ""
+		if row.ReduceRule() != nil || len(row.Lookaheads()) != 0 {
+			attrs += ", style=filled, fillcolor=lightgrey"
+		}
+		if row.AcceptEof() {
+			attrs += ", peripheries=2"
+		}
+		fmt.Fprintf(out, "\ts%d [label=\"%s\"%s];\n", i, label, attrs)
+	}
+
+	for i := 0; i < t.RowsCount(); i++ {
+		row := t.Row(i)
+		for _, id := range row.Terminals() {
+			if to, ok := row.TerminalAction(id); ok {
+				fmt.Fprintf(out, "\ts%d -> s%d [label=\"%s\"];\n", i, to, dotEscape(dumpId(id, g)))
+			}
+		}
+		for _, id := range row.Gotos() {
+			to, _ := row.GotoAction(id)
+			fmt.Fprintf(out, "\ts%d -> s%d [label=\"%s\", style=dashed];\n", i, to, dotEscape(dumpId(id, g)))
+		}
+	}
+	out.WriteString("}\n")
+
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+// dotEscape escapes the given string to be used in double-quoted DOT string
+func dotEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
@@ -0,0 +1,43 @@
+package lr0
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWriteDOT(t *testing.T) {
+	p := New(
+		[]Terminal{
+			NewTerm(tInt, "int").FuncByte(isDigit, bytesToInt),
+			NewTerm(tPlus, `"+"`).Hide().Str("+"),
+		},
+		[]NonTerminalDefinition{
+			NewNT(nGoal, "Goal").Main().Is(nSum),
+			NewNT(nSum, "Sum").Is(nSum, tPlus, tInt).Do(calc2IntSum).Is(tInt),
+		},
+		WithMode(SLR1),
+	)
+	out := &strings.Builder{}
+	if err := WriteDOT(out, p); err != nil {
+		t.Fatal(err)
+	}
+
+	const expected = `digraph LR {
+	rankdir=LR;
+	node [shape=box, fontname=monospace];
+	edge [fontname=monospace];
+	s0 [label="0\lGoal : > Sum $\lSum : > Sum \"+\" int\lSum : > int\l"];
+	s1 [label="1\lSum : int > [$ \"+\"]\l", style=filled, fillcolor=lightgrey];
+	s2 [label="2\lGoal : Sum > $\lSum : Sum > \"+\" int\l", peripheries=2];
+	s3 [label="3\lSum : Sum \"+\" > int\l"];
+	s4 [label="4\lSum : Sum \"+\" int > [$ \"+\"]\l", style=filled, fillcolor=lightgrey];
+	s0 -> s1 [label="int"];
+	s0 -> s2 [label="Sum", style=dashed];
+	s2 -> s3 [label="\"+\""];
+	s3 -> s4 [label="int"];
+}
+`
+	if out.String() != expected {
+		t.Errorf("output:\n%s", out)
+	}
+}
@@ -0,0 +1,275 @@
+package lr0
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Optional returns Id of symbol to use in NonTerminal.Is which matches the
+// given symbol or nothing, like `X?` in EBNF.
+//
+// The value is the value of X or nil, even when X is hidden, so it can be
+// checked whether X is present. The nil becomes zero value of Do handler
+// argument.
+//
+//	NewNT(nVal, "Val").Is(Optional(tMinus), tInt).Do(func(minus string, v int) int { ... })
+//
+// Symbols made by Optional, ZeroOrMore, OneOrMore, List and Group are
+// desugared into generated rules. Lookahead table modes are recommended for
+// them since some of the rules are empty. Every grammar gives own Ids to the
+// symbols in order of use, so Grammar and Table refer them by other Ids than
+// the returned one.
+func Optional(id Id) Id { return ebnfToken(ebnfOptional, id) }
+
+// ZeroOrMore returns Id of symbol to use in NonTerminal.Is which matches the
+// given symbol repeated any times, like `X*` in EBNF. See Optional.
+//
+// The value is `[]any` of values of X, which becomes a slice of Do handler
+// argument type. It's nil for nothing matched.
+//
+//	NewNT(nSum, "Sum").Is(tInt, ZeroOrMore(Group(tPlus, tInt))).Do(func(first int, rest []int) int { ... })
+func ZeroOrMore(id Id) Id { return ebnfToken(ebnfZeroOrMore, id) }
+
+// OneOrMore returns Id of symbol to use in NonTerminal.Is which matches the
+// given symbol repeated at least once, like `X+` in EBNF. The value is the
+// same as for ZeroOrMore.
+func OneOrMore(id Id) Id { return ebnfToken(ebnfOneOrMore, id) }
+
+// List returns Id of symbol to use in NonTerminal.Is which matches the given
+// item repeated at least once and separated by the given separator, like
+// `X (sep X)*` in EBNF. The value is `[]any` of values of items like for
+// ZeroOrMore. Use Optional(List(...)) for list which can be empty.
+//
+//	NewNT(nArgs, "Args").Is(tOpen, Optional(List(nExpr, tComma)), tClose).Do(func(args []int) []int { ... })
+func List(item, sep Id) Id { return ebnfToken(ebnfList, item, sep) }
+
+// Group returns Id of symbol to use in NonTerminal.Is which matches the given
+// sequence, like `(A B C)` in EBNF. Hidden symbols don't produce values like
+// in any rule. The value is the only value, or `[]any` of values when there
+// are many of them, or nil when there are none of them.
+func Group(id Id, ids ...Id) Id { return ebnfToken(ebnfGroup, append([]Id{id}, ids...)...) }
+
+type ebnfKind int
+
+const (
+	ebnfOptional ebnfKind = iota
+	ebnfZeroOrMore
+	ebnfOneOrMore
+	ebnfList
+	ebnfGroup
+)
+
+// ebnfFirstId is the first Id of EBNF symbols in a grammar, next ones are
+// lower. Every grammar gives them in order of use, see grammar.ebnfId.
+const ebnfFirstId Id = -1000
+
+// ebnfFirstToken is the first token returned by Optional and others, next ones
+// are lower. Tokens are global to let use the same symbols in any grammar,
+// but they only refer EBNF symbols in definitions and never get to a table.
+const ebnfFirstToken Id = -1 << 24
+
+type ebnfSymbol struct {
+	kind ebnfKind
+	ids  []Id
+}
+
+var ebnfTokens = struct {
+	sync.Mutex
+	ids     map[string]Id
+	symbols []ebnfSymbol
+}{ids: make(map[string]Id)}
+
+func ebnfToken(kind ebnfKind, ids ...Id) Id {
+	key := fmt.Sprint(kind, ids)
+	ebnfTokens.Lock()
+	defer ebnfTokens.Unlock()
+	if id, ok := ebnfTokens.ids[key]; ok {
+		return id
+	}
+	id := ebnfFirstToken - Id(len(ebnfTokens.symbols))
+	ebnfTokens.symbols = append(ebnfTokens.symbols, ebnfSymbol{kind: kind, ids: ids})
+	ebnfTokens.ids[key] = id
+	return id
+}
+
+func getEbnfToken(id Id) (ebnfSymbol, bool) {
+	ebnfTokens.Lock()
+	defer ebnfTokens.Unlock()
+	i := int(ebnfFirstToken - id)
+	if id > ebnfFirstToken || i >= len(ebnfTokens.symbols) {
+		return ebnfSymbol{}, false
+	}
+	return ebnfTokens.symbols[i], true
+}
+
+// ebnfId returns Id of EBNF symbol in this grammar for the given token, or
+// the given Id itself when it's not a token. Ids are given in order of use,
+// so they don't depend on other grammars and tokens.
+func (g *grammar) ebnfId(id Id) Id {
+	if local, ok := g.ebnfIds[id]; ok {
+		return local
+	}
+	s, ok := getEbnfToken(id)
+	if !ok {
+		return id
+	}
+	local := ebnfFirstId - Id(len(g.ebnfIds))
+	g.ebnfIds[id] = local
+	ids := make([]Id, len(s.ids))
+	for i, sub := range s.ids {
+		ids[i] = g.ebnfId(sub)
+	}
+	g.ebnf[local] = ebnfSymbol{kind: s.kind, ids: ids}
+	return local
+}
+
+// getEbnfSymbol returns EBNF symbol by its Id in this grammar
+func (g *grammar) getEbnfSymbol(id Id) (ebnfSymbol, bool) {
+	s, ok := g.ebnf[id]
+	return s, ok
+}
+
+// withEbnfIds returns the rule with tokens of EBNF symbols in definition
+// replaced with their Ids in this grammar
+func (g *grammar) withEbnfIds(r Rule) Rule {
+	def := r.Definition()
+	var local []Id
+	for i, id := range def {
+		if l := g.ebnfId(id); l != id {
+			if local == nil {
+				local = append([]Id(nil), def...)
+			}
+			local[i] = l
+		}
+	}
+	if local == nil {
+		return r
+	}
+	if rr, ok := r.(*rule); ok {
+		// rules of NonTerminal are made for this grammar
+		rr.definition = local
+		return rr
+	}
+	return &ebnfIdsRule{Rule: r, definition: local}
+}
+
+// ebnfIdsRule is a Rule from custom NonTerminalDefinition with Ids of EBNF
+// symbols of the grammar in definition
+type ebnfIdsRule struct {
+	Rule
+	definition []Id
+}
+
+func (r *ebnfIdsRule) Definition() []Id { return r.definition }
+
+// ebnfNonTerminal is a generated non-terminal for EBNF symbol
+type ebnfNonTerminal struct {
+	id   Id
+	name string
+}
+
+func (n *ebnfNonTerminal) Id() Id       { return n.id }
+func (n *ebnfNonTerminal) Name() string { return n.name }
+
+// ebnfName renders EBNF symbol name like `X?`
+func ebnfName(s ebnfSymbol, g *grammar) string {
+	name := func(id Id) string {
+		sub, ok := g.getEbnfSymbol(id)
+		if !ok {
+			return dumpId(id, g)
+		}
+		if sub.kind == ebnfList {
+			return "(" + ebnfName(sub, g) + ")"
+		}
+		return ebnfName(sub, g)
+	}
+	switch s.kind {
+	case ebnfOptional:
+		return name(s.ids[0]) + "?"
+	case ebnfZeroOrMore:
+		return name(s.ids[0]) + "*"
+	case ebnfOneOrMore:
+		return name(s.ids[0]) + "+"
+	case ebnfList:
+		return name(s.ids[0]) + " (" + name(s.ids[1]) + " " + name(s.ids[0]) + ")*"
+	default:
+		res := "("
+		for i, id := range s.ids {
+			if i > 0 {
+				res += " "
+			}
+			res += name(id)
+		}
+		return res + ")"
+	}
+}
+
+// ebnfRules generates rules for the EBNF symbol. All values are passed to
+// calc funcs of the rules regardless of hidden symbols.
+func ebnfRules(id Id, s ebnfSymbol, l NamedHiddenRegistry) []Rule {
+	newR := func(calc calcFunc, def ...Id) Rule {
+		return &rule{
+			subject:    id,
+			definition: def,
+			calc:       calc,
+			hidden:     map[int]struct{}{},
+			nameReg:    l,
+		}
+	}
+	x := s.ids[0]
+	switch s.kind {
+	case ebnfOptional:
+		return []Rule{
+			newR(calcDefaultBubble, x),
+			newR(calcDefaultNil),
+		}
+	case ebnfZeroOrMore:
+		return []Rule{
+			newR(calcEbnfAppend(1), id, x),
+			newR(calcDefaultNil),
+		}
+	case ebnfOneOrMore:
+		return []Rule{
+			newR(calcEbnfAppend(1), id, x),
+			newR(calcEbnfFirst, x),
+		}
+	case ebnfList:
+		return []Rule{
+			newR(calcEbnfAppend(2), id, s.ids[1], x),
+			newR(calcEbnfFirst, x),
+		}
+	default:
+		var visible []int
+		for i, id := range s.ids {
+			if !l.IsHidden(id) {
+				visible = append(visible, i)
+			}
+		}
+		return []Rule{
+			newR(func(v []any) (any, error) {
+				switch len(visible) {
+				case 0:
+					return nil, nil
+				case 1:
+					return v[visible[0]], nil
+				}
+				res := make([]any, 0, len(visible))
+				for _, i := range visible {
+					res = append(res, v[i])
+				}
+				return res, nil
+			}, s.ids...),
+		}
+	}
+}
+
+// calcEbnfAppend returns calc func to append value with the given index to
+// the list in the first value
+func calcEbnfAppend(index int) calcFunc {
+	return func(v []any) (any, error) {
+		list, _ := v[0].([]any)
+		return append(list, v[index]), nil
+	}
+}
+
+func calcEbnfFirst(v []any) (any, error) { return []any{v[0]}, nil }
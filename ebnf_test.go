@@ -0,0 +1,140 @@
+package lr0
+
+import (
+	"fmt"
+	"testing"
+	"unicode"
+)
+
+func TestEbnf(t *testing.T) {
+	const (
+		tOpen Id = nGoal + 1 + iota
+		tClose
+		tComma
+		nCall
+	)
+	// Goal : Call $
+	// Call : ident "(" (Sum ("," Sum)*)? ")"
+	// Sum  : Val ("+" Val)*
+	// Val  : "-"? int
+	g := newGrammar(
+		[]Terminal{
+			NewTerm(tInt, "int").FuncByte(isDigit, bytesToInt),
+			NewTerm(tIdent, "ident").Func(matchIdentifier),
+			NewTerm(tPlus, `"+"`).Hide().Str("+"),
+			NewTerm(tMinus, `"-"`).Hide().Str("-"),
+			NewTerm(tOpen, `"("`).Hide().Str("("),
+			NewTerm(tClose, `")"`).Hide().Str(")"),
+			NewTerm(tComma, `","`).Hide().Str(","),
+			NewWhitespace().FuncRune(unicode.IsSpace),
+		},
+		[]NonTerminalDefinition{
+			NewNT(nGoal, "Goal").Main().Is(nCall),
+			NewNT(nCall, "Call").
+				Is(tIdent, tOpen, Optional(List(nSum, tComma)), tClose).
+				Do(func(name string, args []int) string { return fmt.Sprint(name, args, args == nil) }),
+			NewNT(nSum, "Sum").
+				Is(nVal, ZeroOrMore(Group(tPlus, nVal))).
+				Do(func(first int, rest []int) int {
+					for _, v := range rest {
+						first += v
+					}
+					return first
+				}),
+			NewNT(nVal, "Val").
+				Is(Optional(tMinus), tInt).
+				Do(func(minus string, v int) int {
+					if minus != "" {
+						return -v
+					}
+					return v
+				}),
+		},
+	)
+
+	if s := g.RulesFor(nCall)[0].String(); s != `Call : ident "(" (Sum ("," Sum)*)? ")"` {
+		t.Errorf("rule: %s", s)
+	}
+	if s := g.RulesFor(nSum)[0].String(); s != `Sum : Val ("+" Val)*` {
+		t.Errorf("rule: %s", s)
+	}
+	if Optional(tMinus) != Optional(tMinus) || Optional(tMinus) == Optional(tPlus) {
+		t.Error("EBNF symbols are not reused")
+	}
+
+	for _, mode := range []TableMode{SLR1, LALR1, LR1} {
+		p := newParser(g, WithMode(mode))
+		for input, result := range map[string]string{
+			"f()":                 "f[] true",
+			"f(1)":                "f[1] false",
+			"f(1, -2 + 3, 4+5+6)": "f[1 1 15] false",
+		} {
+			t.Run(fmt.Sprintf("%v: %s", mode, input), func(t *testing.T) {
+				v, err := p.Parse(NewState([]byte(input)))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if v != result {
+					t.Errorf("result %#v", v)
+				}
+			})
+		}
+	}
+
+	t.Run("group values", func(t *testing.T) {
+		// Goal : Sum $
+		// Sum  : (ident "=" int)+
+		p := New(
+			[]Terminal{
+				NewTerm(tInt, "int").FuncByte(isDigit, bytesToInt),
+				NewTerm(tIdent, "ident").Func(matchIdentifier),
+				NewTerm(tMinus, `"="`).Hide().Str("="),
+				NewWhitespace().FuncRune(unicode.IsSpace),
+			},
+			[]NonTerminalDefinition{
+				NewNT(nGoal, "Goal").Main().Is(nSum),
+				NewNT(nSum, "Sum").Is(OneOrMore(Group(tIdent, tMinus, tInt))).
+					Do(func(list [][]any) string { return fmt.Sprint(list) }),
+			},
+			WithMode(SLR1),
+		)
+		v, err := p.Parse(NewState([]byte("a = 1 b = 2")))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v != "[[a 1] [b 2]]" {
+			t.Errorf("result %#v", v)
+		}
+	})
+}
+
+func TestEbnf_Ids(t *testing.T) {
+	newG := func() *grammar {
+		return newGrammar(
+			[]Terminal{
+				NewTerm(tInt, "int").FuncByte(isDigit, bytesToInt),
+				NewTerm(tPlus, `"+"`).Hide().Str("+"),
+			},
+			[]NonTerminalDefinition{
+				NewNT(nGoal, "Goal").Main().Is(nSum),
+				NewNT(nSum, "Sum").Is(tInt, ZeroOrMore(Group(tPlus, tInt))).Do(func(int, []any) int { return 0 }),
+			},
+		)
+	}
+	g1 := newG()
+	// more tokens don't change Ids in another grammar
+	_ = Optional(OneOrMore(tInt))
+	g2 := newG()
+	for _, g := range []*grammar{g1, g2} {
+		def := g.RulesFor(nSum)[0].Definition()
+		if len(def) != 2 || def[1] != ebnfFirstId {
+			t.Errorf("definition %v", def)
+		}
+		if s := fmt.Sprint(g.RulesFor(ebnfFirstId - 1)); s != `[("+" int) : "+" int]` {
+			t.Errorf("group rules %s", s)
+		}
+	}
+	if f1, f2 := grammarFingerprint(g1, newConfig(nil)), grammarFingerprint(g2, newConfig(nil)); f1 != f2 {
+		t.Error("fingerprints differ")
+	}
+}
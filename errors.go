@@ -57,8 +57,21 @@ type withSource struct {
 	src StatePrinter
 }
 
+// positioner is a StatePrinter which knows its Position, like State
+type positioner interface {
+	Position() Position
+}
+
+// position returns ` at file:line:column` if it's known
+func (w *withSource) position() string {
+	if p, ok := w.src.(positioner); ok {
+		return " at " + p.Position().String()
+	}
+	return ""
+}
+
 func (w *withSource) Error() string {
-	return w.error.Error() + " near " + fmt.Sprintf("%s", w.src)
+	return w.error.Error() + w.position() + " near " + fmt.Sprintf("%s", w.src)
 }
 
 func (w *withSource) Unwrap() error {
@@ -70,6 +83,7 @@ func (w *withSource) Format(s fmt.State, verb rune) {
 	case 'v':
 		if s.Flag('+') {
 			io.WriteString(s, w.error.Error())
+			io.WriteString(s, w.position())
 			io.WriteString(s, " near:\n")
 			w.src.Format(s, verb)
 			return
@@ -79,3 +93,102 @@ func (w *withSource) Format(s fmt.State, verb rune) {
 		io.WriteString(s, w.Error())
 	}
 }
+
+// DefinitionErrors is a list of all definition errors found by NewE
+//
+// Every error in the list wraps ErrDefine, so the list matches it too:
+//
+//	errors.Is(err, ErrDefine)
+type DefinitionErrors []error
+
+func (e DefinitionErrors) Error() string {
+	s := ""
+	for i, err := range e {
+		if i > 0 {
+			s += "\n"
+		}
+		s += err.Error()
+	}
+	return s
+}
+
+func (e DefinitionErrors) Unwrap() []error { return e }
+
+// appendDefinitionErrors appends the given error to the list. A nested
+// DefinitionErrors list is flattened.
+func appendDefinitionErrors(list []error, err error) []error {
+	if err == nil {
+		return list
+	}
+	if l, ok := err.(DefinitionErrors); ok {
+		for _, e := range l {
+			list = appendDefinitionErrors(list, e)
+		}
+		return list
+	}
+	return append(list, err)
+}
+
+// joinDefinitionErrors returns nil for empty list, the only error itself for
+// single item list, or DefinitionErrors otherwise
+func joinDefinitionErrors(list []error) error {
+	switch len(list) {
+	case 0:
+		return nil
+	case 1:
+		return list[0]
+	default:
+		return DefinitionErrors(list)
+	}
+}
+
+// ParseErrors is a list of errors found by parser recovering from syntax
+// errors with Error symbol. Parsing errors match ErrParse:
+//
+//	errors.Is(err, ErrParse)
+//
+// Once any error is recovered, the list is returned even for the only error.
+// When the input is parsed anyway, the list is returned together with result.
+// Otherwise the last error in the list is the one which stopped parsing. An
+// error which stopped parsing before any recovery is returned as is.
+type ParseErrors []error
+
+func (e ParseErrors) Error() string {
+	s := ""
+	for i, err := range e {
+		if i > 0 {
+			s += "\n"
+		}
+		s += err.Error()
+	}
+	return s
+}
+
+func (e ParseErrors) Unwrap() []error { return e }
+
+// panicError converts a value recovered from panic to error. A value which is
+// not an error is wrapped to ErrInternal.
+func panicError(e any) error {
+	if err, ok := e.(error); ok {
+		return err
+	}
+	return errors.Wrapf(ErrInternal, "%v", e)
+}
+
+// catchDefine calls fn and returns an error wrapping ErrDefine if it was
+// raised by panic in fn. Other panics are passed through.
+func catchDefine(fn func()) (err error) {
+	defer func() {
+		e := recover()
+		if e == nil {
+			return
+		}
+		if er, ok := e.(error); ok && errors.Is(er, ErrDefine) {
+			err = er
+			return
+		}
+		panic(e)
+	}()
+	fn()
+	return nil
+}
@@ -1,6 +1,7 @@
 package lr0
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/pkg/errors"
@@ -14,4 +15,7 @@ func TestParseError(t *testing.T) {
 	if !errors.Is(err, ErrParse) {
 		t.Errorf("unexpected false: %v", err)
 	}
+	if s := err.Error(); !strings.HasPrefix(s, "foo bar: parse error at 1:36 near ⟪") {
+		t.Errorf("unexpected message: %s", s)
+	}
 }
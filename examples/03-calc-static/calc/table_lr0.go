@@ -0,0 +1,261 @@
+// Code generated by lr0gen. DO NOT EDIT.
+
+package calc
+
+import "github.com/vovan-ve/go-lr0-parser"
+
+var Table = &lr0.TableData{
+	Fingerprint: "c1b925f19a83d9ed109e984aa03d7eda587f2ca70aba3612000abb6a21a5060e",
+	Mode:        lr0.LALR1,
+	Cores:       16,
+	Rows: []lr0.RowData{
+		// 0
+		// Goal : > Sum $
+		// Sum : > Sum "+" Prod
+		// Sum : > Sum "-" Prod
+		// Sum : > Prod
+		// Prod : > Prod "*" Val
+		// Prod : > Prod "/" Val
+		// Prod : > Val
+		// Val : > int
+		// Val : > "(" Sum ")"
+		{
+			Shift: []lr0.Action{
+				{Id: 1, To: 1}, // int
+				{Id: 6, To: 2}, // "("
+			},
+			Goto: []lr0.Action{
+				{Id: 8, To: 3},  // Val
+				{Id: 9, To: 4},  // Prod
+				{Id: 10, To: 5}, // Sum
+			},
+			Reduce: -1,
+		},
+		// 1
+		// Val : int > [$ "+" "-" "*" "/" ")"]
+		{
+			Reduce: 7, // Val : int
+			Lookahead: []lr0.Action{
+				{Id: -2, To: 7}, // $ -> Val : int
+				{Id: 2, To: 7},  // "+" -> Val : int
+				{Id: 3, To: 7},  // "-" -> Val : int
+				{Id: 4, To: 7},  // "*" -> Val : int
+				{Id: 5, To: 7},  // "/" -> Val : int
+				{Id: 7, To: 7},  // ")" -> Val : int
+			},
+		},
+		// 2
+		// Val : "(" > Sum ")"
+		// Sum : > Sum "+" Prod
+		// Sum : > Sum "-" Prod
+		// Sum : > Prod
+		// Prod : > Prod "*" Val
+		// Prod : > Prod "/" Val
+		// Prod : > Val
+		// Val : > int
+		// Val : > "(" Sum ")"
+		{
+			Shift: []lr0.Action{
+				{Id: 1, To: 1}, // int
+				{Id: 6, To: 2}, // "("
+			},
+			Goto: []lr0.Action{
+				{Id: 8, To: 3},  // Val
+				{Id: 9, To: 4},  // Prod
+				{Id: 10, To: 6}, // Sum
+			},
+			Reduce: -1,
+		},
+		// 3
+		// Prod : Val > [$ "+" "-" "*" "/" ")"]
+		{
+			Reduce: 6, // Prod : Val
+			Lookahead: []lr0.Action{
+				{Id: -2, To: 6}, // $ -> Prod : Val
+				{Id: 2, To: 6},  // "+" -> Prod : Val
+				{Id: 3, To: 6},  // "-" -> Prod : Val
+				{Id: 4, To: 6},  // "*" -> Prod : Val
+				{Id: 5, To: 6},  // "/" -> Prod : Val
+				{Id: 7, To: 6},  // ")" -> Prod : Val
+			},
+		},
+		// 4
+		// Sum : Prod > [$ "+" "-" ")"]
+		// Prod : Prod > "*" Val
+		// Prod : Prod > "/" Val
+		{
+			Shift: []lr0.Action{
+				{Id: 4, To: 7}, // "*"
+				{Id: 5, To: 8}, // "/"
+			},
+			Reduce: -1,
+			Lookahead: []lr0.Action{
+				{Id: -2, To: 3}, // $ -> Sum : Prod
+				{Id: 2, To: 3},  // "+" -> Sum : Prod
+				{Id: 3, To: 3},  // "-" -> Sum : Prod
+				{Id: 7, To: 3},  // ")" -> Sum : Prod
+			},
+		},
+		// 5
+		// Goal : Sum > $
+		// Sum : Sum > "+" Prod
+		// Sum : Sum > "-" Prod
+		{
+			AcceptEof: true,
+			Shift: []lr0.Action{
+				{Id: 2, To: 9},  // "+"
+				{Id: 3, To: 10}, // "-"
+			},
+			Reduce: -1,
+		},
+		// 6
+		// Val : "(" Sum > ")"
+		// Sum : Sum > "+" Prod
+		// Sum : Sum > "-" Prod
+		{
+			Shift: []lr0.Action{
+				{Id: 2, To: 9},  // "+"
+				{Id: 3, To: 10}, // "-"
+				{Id: 7, To: 11}, // ")"
+			},
+			Reduce: -1,
+		},
+		// 7
+		// Prod : Prod "*" > Val
+		// Val : > int
+		// Val : > "(" Sum ")"
+		{
+			Shift: []lr0.Action{
+				{Id: 1, To: 1}, // int
+				{Id: 6, To: 2}, // "("
+			},
+			Goto: []lr0.Action{
+				{Id: 8, To: 12}, // Val
+			},
+			Reduce: -1,
+		},
+		// 8
+		// Prod : Prod "/" > Val
+		// Val : > int
+		// Val : > "(" Sum ")"
+		{
+			Shift: []lr0.Action{
+				{Id: 1, To: 1}, // int
+				{Id: 6, To: 2}, // "("
+			},
+			Goto: []lr0.Action{
+				{Id: 8, To: 13}, // Val
+			},
+			Reduce: -1,
+		},
+		// 9
+		// Sum : Sum "+" > Prod
+		// Prod : > Prod "*" Val
+		// Prod : > Prod "/" Val
+		// Prod : > Val
+		// Val : > int
+		// Val : > "(" Sum ")"
+		{
+			Shift: []lr0.Action{
+				{Id: 1, To: 1}, // int
+				{Id: 6, To: 2}, // "("
+			},
+			Goto: []lr0.Action{
+				{Id: 8, To: 3},  // Val
+				{Id: 9, To: 14}, // Prod
+			},
+			Reduce: -1,
+		},
+		// 10
+		// Sum : Sum "-" > Prod
+		// Prod : > Prod "*" Val
+		// Prod : > Prod "/" Val
+		// Prod : > Val
+		// Val : > int
+		// Val : > "(" Sum ")"
+		{
+			Shift: []lr0.Action{
+				{Id: 1, To: 1}, // int
+				{Id: 6, To: 2}, // "("
+			},
+			Goto: []lr0.Action{
+				{Id: 8, To: 3},  // Val
+				{Id: 9, To: 15}, // Prod
+			},
+			Reduce: -1,
+		},
+		// 11
+		// Val : "(" Sum ")" > [$ "+" "-" "*" "/" ")"]
+		{
+			Reduce: 8, // Val : "(" Sum ")"
+			Lookahead: []lr0.Action{
+				{Id: -2, To: 8}, // $ -> Val : "(" Sum ")"
+				{Id: 2, To: 8},  // "+" -> Val : "(" Sum ")"
+				{Id: 3, To: 8},  // "-" -> Val : "(" Sum ")"
+				{Id: 4, To: 8},  // "*" -> Val : "(" Sum ")"
+				{Id: 5, To: 8},  // "/" -> Val : "(" Sum ")"
+				{Id: 7, To: 8},  // ")" -> Val : "(" Sum ")"
+			},
+		},
+		// 12
+		// Prod : Prod "*" Val > [$ "+" "-" "*" "/" ")"]
+		{
+			Reduce: 4, // Prod : Prod "*" Val
+			Lookahead: []lr0.Action{
+				{Id: -2, To: 4}, // $ -> Prod : Prod "*" Val
+				{Id: 2, To: 4},  // "+" -> Prod : Prod "*" Val
+				{Id: 3, To: 4},  // "-" -> Prod : Prod "*" Val
+				{Id: 4, To: 4},  // "*" -> Prod : Prod "*" Val
+				{Id: 5, To: 4},  // "/" -> Prod : Prod "*" Val
+				{Id: 7, To: 4},  // ")" -> Prod : Prod "*" Val
+			},
+		},
+		// 13
+		// Prod : Prod "/" Val > [$ "+" "-" "*" "/" ")"]
+		{
+			Reduce: 5, // Prod : Prod "/" Val
+			Lookahead: []lr0.Action{
+				{Id: -2, To: 5}, // $ -> Prod : Prod "/" Val
+				{Id: 2, To: 5},  // "+" -> Prod : Prod "/" Val
+				{Id: 3, To: 5},  // "-" -> Prod : Prod "/" Val
+				{Id: 4, To: 5},  // "*" -> Prod : Prod "/" Val
+				{Id: 5, To: 5},  // "/" -> Prod : Prod "/" Val
+				{Id: 7, To: 5},  // ")" -> Prod : Prod "/" Val
+			},
+		},
+		// 14
+		// Sum : Sum "+" Prod > [$ "+" "-" ")"]
+		// Prod : Prod > "*" Val
+		// Prod : Prod > "/" Val
+		{
+			Shift: []lr0.Action{
+				{Id: 4, To: 7}, // "*"
+				{Id: 5, To: 8}, // "/"
+			},
+			Reduce: -1,
+			Lookahead: []lr0.Action{
+				{Id: -2, To: 1}, // $ -> Sum : Sum "+" Prod
+				{Id: 2, To: 1},  // "+" -> Sum : Sum "+" Prod
+				{Id: 3, To: 1},  // "-" -> Sum : Sum "+" Prod
+				{Id: 7, To: 1},  // ")" -> Sum : Sum "+" Prod
+			},
+		},
+		// 15
+		// Sum : Sum "-" Prod > [$ "+" "-" ")"]
+		// Prod : Prod > "*" Val
+		// Prod : Prod > "/" Val
+		{
+			Shift: []lr0.Action{
+				{Id: 4, To: 7}, // "*"
+				{Id: 5, To: 8}, // "/"
+			},
+			Reduce: -1,
+			Lookahead: []lr0.Action{
+				{Id: -2, To: 2}, // $ -> Sum : Sum "-" Prod
+				{Id: 2, To: 2},  // "+" -> Sum : Sum "-" Prod
+				{Id: 3, To: 2},  // "-" -> Sum : Sum "-" Prod
+				{Id: 7, To: 2},  // ")" -> Sum : Sum "-" Prod
+			},
+		},
+	},
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/vovan-ve/go-lr0-parser"
+	"github.com/vovan-ve/go-lr0-parser/examples/03-calc-static/calc"
+)
+
+var parser = newParser()
+
+func newParser() lr0.Parser {
+	terminals, rules, options := calc.Grammar()
+	return lr0.New(terminals, rules, append(options, lr0.WithTable(calc.Table))...)
+}
+
+func main() {
+	if len(os.Args) <= 1 {
+		log.Println("no args to calc")
+		return
+	}
+	for i, input := range os.Args[1:] {
+		fmt.Printf("%d> %s\t=> ", i, input)
+		result, err := parser.Parse(lr0.NewState([]byte(input)))
+		if err != nil {
+			fmt.Println("Error:", err)
+		} else {
+			fmt.Println(result)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package lr0
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Pos is a compact position in FileSet, like `token.Pos` in `go/token`.
+// Positions of different buffers in the same FileSet don't overlap, so they
+// can be compared.
+type Pos int
+
+// NoPos is zero value of Pos which is not a position
+const NoPos Pos = 0
+
+// Position describes a position in a buffer
+type Position struct {
+	// Filename is name of the buffer if any
+	Filename string
+	// Offset is 0-based offset in bytes
+	Offset int
+	// Line is 1-based line number
+	Line int
+	// Column is 1-based column number, see State.LineColumn
+	Column int
+}
+
+// IsValid checks if the Position is known
+func (p Position) IsValid() bool { return p.Line > 0 }
+
+// String returns `file:line:column`, or `line:column` without Filename, or
+// `-` for invalid Position
+func (p Position) String() string {
+	if !p.IsValid() {
+		return "-"
+	}
+	if p.Filename == "" {
+		return fmt.Sprintf("%d:%d", p.Line, p.Column)
+	}
+	return fmt.Sprintf("%s:%d:%d", p.Filename, p.Line, p.Column)
+}
+
+// FileSet is a registry of named buffers to parse many inputs, like
+// `token.FileSet` in `go/token`
+//
+//	fs := NewFileSet()
+//	a, err := parser.Parse(fs.AddFile("a.conf", inputA))
+//	b, err := parser.Parse(fs.AddFile("b.conf", inputB))
+//	...
+//	fmt.Println(fs.Position(pos)) // b.conf:3:14
+type FileSet struct {
+	mu    sync.Mutex
+	base  Pos
+	files []*fileSetItem
+}
+
+type fileSetItem struct {
+	start  *State
+	length int
+}
+
+// NewFileSet creates new empty FileSet
+func NewFileSet() *FileSet {
+	return &FileSet{base: 1}
+}
+
+// AddFile adds the buffer with the given name and returns State pointing to
+// its start
+func (fs *FileSet) AddFile(name string, input []byte) *State {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	st := NewNamedState(name, input)
+	// base of State is the position before its start, see State.Pos
+	st.file.base = fs.base - 1
+	fs.files = append(fs.files, &fileSetItem{start: st, length: len(input)})
+	// EOF position is valid too
+	fs.base += Pos(len(input)) + 1
+	return st
+}
+
+// State returns State of the given position, or nil if it's not in FileSet
+func (fs *FileSet) State(p Pos) *State {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	i := sort.Search(len(fs.files), func(i int) bool {
+		f := fs.files[i]
+		return f.start.Pos()+Pos(f.length) >= p
+	})
+	if i == len(fs.files) || p < fs.files[i].start.Pos() {
+		return nil
+	}
+	f := fs.files[i]
+	return f.start.to(int(p - f.start.Pos()))
+}
+
+// Position returns Position of the given Pos, or zero Position if it's not in
+// FileSet
+func (fs *FileSet) Position(p Pos) Position {
+	st := fs.State(p)
+	if st == nil {
+		return Position{}
+	}
+	return st.Position()
+}
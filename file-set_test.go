@@ -0,0 +1,55 @@
+package lr0
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestFileSet(t *testing.T) {
+	fs := NewFileSet()
+	a := fs.AddFile("a.txt", []byte("foo\nbar"))
+	b := fs.AddFile("b.txt", []byte("lorem\nipsum"))
+
+	if a.Name() != "a.txt" || b.Name() != "b.txt" {
+		t.Errorf("names %q %q", a.Name(), b.Name())
+	}
+	posA := a.to(5).Pos()
+	posB := b.to(7).Pos()
+	if posA == NoPos || posB <= a.to(a.Len()).Pos() {
+		t.Errorf("positions overlap: %v, %v", posA, posB)
+	}
+	for p, expect := range map[Pos]string{
+		posA:         "a.txt:2:2",
+		posB:         "b.txt:2:2",
+		a.Pos():      "a.txt:1:1",
+		b.Pos():      "b.txt:1:1",
+		b.Pos() - 1:  "a.txt:2:4",
+		NoPos:        "-",
+		posB + 10000: "-",
+	} {
+		if s := fs.Position(p).String(); s != expect {
+			t.Errorf("position %d is %s, expected %s", p, s, expect)
+		}
+	}
+	if st := fs.State(posB); st == nil || st.Offset() != 7 || st.Name() != "b.txt" {
+		t.Errorf("state %v", st)
+	}
+
+	err := WithSource(NewParseError("foo"), b.to(7).WithTabWidth(4))
+	if !errors.Is(err, ErrParse) {
+		t.Fatal("another error", err)
+	}
+	if s := err.Error(); s != "foo: parse error at b.txt:2:2 near ⟪lorem␊i⟫⏵⟪psum⟫" {
+		t.Errorf("error: %s", s)
+	}
+}
+
+func TestPosition_String(t *testing.T) {
+	if s := NewState([]byte("a\nb")).to(2).Position().String(); s != "2:1" {
+		t.Errorf("unnamed: %s", s)
+	}
+	if s := NewNamedState("x.conf", []byte("a\nb")).to(3).Position().String(); s != "x.conf:2:2" {
+		t.Errorf("named: %s", s)
+	}
+}
@@ -0,0 +1,110 @@
+package lr0
+
+import (
+	"fmt"
+	"go/format"
+	"io"
+	"strings"
+)
+
+// WriteGo writes Go source file of package `pkg` which declares variable
+// `name` of type *TableData with the table of the Parser, so the table will
+// not be built at run-time. Use it with WithTable:
+//
+//	New(terminals, rules, WithTable(name))
+//
+// The table is written with slice literals in the same form as the Parser
+// uses it, so nothing is built on initialization. Items of every state and
+// names of symbols are written in comments to make the table reviewable in
+// diffs. See cmd/lr0gen to use it with go generate.
+func WriteGo(w io.Writer, p Parser, pkg, name string) error {
+	var (
+		g     = p.Grammar()
+		t     = p.Table()
+		data  = t.Data()
+		out   = &strings.Builder{}
+		names = func(list []Action, rules bool) string {
+			res := ""
+			for _, a := range list {
+				comment := dumpId(a.Id, g)
+				if rules {
+					comment += " -> " + g.Rule(a.To).String()
+				}
+				res += fmt.Sprintf("{Id: %d, To: %d}, // %s\n", a.Id, a.To, goComment(comment))
+			}
+			return res
+		}
+	)
+
+	fmt.Fprintf(out, "// Code generated by lr0gen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(out, "package %s\n\n", pkg)
+	fmt.Fprintf(out, "import \"github.com/vovan-ve/go-lr0-parser\"\n\n")
+	fmt.Fprintf(out, "var %s = &lr0.TableData{\n", name)
+	fmt.Fprintf(out, "Fingerprint: %q,\n", data.Fingerprint)
+	fmt.Fprintf(out, "Mode: %s,\n", goTableMode(data.Mode))
+	fmt.Fprintf(out, "Cores: %d,\n", data.Cores)
+	fmt.Fprintf(out, "Rows: []lr0.RowData{\n")
+	for i, rd := range data.Rows {
+		fmt.Fprintf(out, "// %d\n", i)
+		for _, it := range t.Items(i) {
+			fmt.Fprintf(out, "// %s\n", goComment(it))
+		}
+		fmt.Fprintf(out, "{\n")
+		if rd.AcceptEof {
+			fmt.Fprintf(out, "AcceptEof: true,\n")
+		}
+		if len(rd.Shift) != 0 {
+			fmt.Fprintf(out, "Shift: []lr0.Action{\n%s},\n", names(rd.Shift, false))
+		}
+		if len(rd.Goto) != 0 {
+			fmt.Fprintf(out, "Goto: []lr0.Action{\n%s},\n", names(rd.Goto, false))
+		}
+		if rd.Reduce != -1 {
+			fmt.Fprintf(out, "Reduce: %d, // %s\n", rd.Reduce, goComment(g.Rule(rd.Reduce).String()))
+		} else {
+			fmt.Fprintf(out, "Reduce: -1,\n")
+		}
+		if len(rd.Lookahead) != 0 {
+			fmt.Fprintf(out, "Lookahead: []lr0.Action{\n%s},\n", names(rd.Lookahead, true))
+		}
+		if len(rd.Denied) != 0 {
+			fmt.Fprintf(out, "Denied: []lr0.Id{")
+			for j, id := range rd.Denied {
+				if j > 0 {
+					fmt.Fprintf(out, ", ")
+				}
+				fmt.Fprintf(out, "%d", id)
+			}
+			fmt.Fprintf(out, "}, // %s\n", goComment(dumpIds(rd.Denied, g)))
+		}
+		fmt.Fprintf(out, "},\n")
+	}
+	fmt.Fprintf(out, "},\n}\n")
+
+	src, err := format.Source([]byte(out.String()))
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(src)
+	return err
+}
+
+func goTableMode(m TableMode) string {
+	switch m {
+	case LR0:
+		return "lr0.LR0"
+	case SLR1:
+		return "lr0.SLR1"
+	case LALR1:
+		return "lr0.LALR1"
+	case LR1:
+		return "lr0.LR1"
+	default:
+		return fmt.Sprintf("lr0.TableMode(%d)", int(m))
+	}
+}
+
+// goComment makes the given string safe for single line comment
+func goComment(s string) string {
+	return strings.NewReplacer("\n", `\n`, "\r", `\r`).Replace(s)
+}
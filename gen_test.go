@@ -0,0 +1,53 @@
+package lr0
+
+import (
+	goparser "go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+func TestWriteGo(t *testing.T) {
+	p := New(
+		[]Terminal{
+			NewTerm(tInt, "int").FuncByte(isDigit, bytesToInt),
+			NewTerm(tPlus, `"+"`).Hide().Str("+"),
+		},
+		[]NonTerminalDefinition{
+			NewNT(nGoal, "Goal").Main().Is(nSum),
+			NewNT(nSum, "Sum").Is(nSum, tPlus, tInt).Do(calc2IntSum).Is(tInt),
+		},
+		WithMode(SLR1),
+	)
+	out := &strings.Builder{}
+	if err := WriteGo(out, p, "foo", "fooTable"); err != nil {
+		t.Fatal(err)
+	}
+	src := out.String()
+
+	if _, err := goparser.ParseFile(token.NewFileSet(), "foo.go", src, 0); err != nil {
+		t.Fatalf("invalid source: %v\n%s", err, src)
+	}
+	const row1 = `
+		// 1
+		// Sum : int > [$ "+"]
+		{
+			Reduce: 2, // Sum : int
+			Lookahead: []lr0.Action{
+				{Id: -2, To: 2}, // $ -> Sum : int
+				{Id: 4, To: 2},  // "+" -> Sum : int
+			},
+		},
+`
+	for _, part := range []string{
+		"// Code generated by lr0gen. DO NOT EDIT.\n\npackage foo\n",
+		"var fooTable = &lr0.TableData{\n",
+		"\tFingerprint: \"" + p.Table().Data().Fingerprint + "\",\n",
+		"\tMode:        lr0.SLR1,\n",
+		row1,
+	} {
+		if !strings.Contains(src, part) {
+			t.Errorf("no %q in source:\n%s", part, src)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package lr0
+
+import (
+	"sort"
+)
+
+func (g *grammar) Terminals() []Id { return g.GetTerminalIdsSet().Ids() }
+
+func (g *grammar) NonTerminals() []Id {
+	s := newIdSet()
+	for id := range g.subjectsIndices {
+		s.Add(id)
+	}
+	return s.Ids()
+}
+
+func (g *grammar) IsNullable(id Id) bool { return g.Sets().nullable.Has(id) }
+func (g *grammar) Nullable() []Id        { return g.Sets().nullable.Ids() }
+
+func (g *grammar) First(id Id) []Id {
+	if g.IsTerminal(id) {
+		return []Id{id}
+	}
+	if f, ok := g.Sets().first[id]; ok {
+		return f.Ids()
+	}
+	return nil
+}
+
+func (g *grammar) Follow(id Id) []Id {
+	if f, ok := g.Sets().follow[id]; ok {
+		return f.Ids()
+	}
+	return nil
+}
+
+func (g *grammar) Unreachable() []Id {
+	reached := newIdSet()
+	var queue []Id
+	for _, r := range g.MainRules() {
+		reached.Add(r.Subject())
+		queue = append(queue, r.Subject())
+	}
+	for len(queue) != 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, r := range g.RulesFor(id) {
+			for _, next := range r.Definition() {
+				if g.IsTerminal(next) || reached.Has(next) {
+					continue
+				}
+				reached.Add(next)
+				queue = append(queue, next)
+			}
+		}
+	}
+	res := newIdSet()
+	for id := range g.subjectsIndices {
+		if !reached.Has(id) {
+			res.Add(id)
+		}
+	}
+	return res.Ids()
+}
+
+func (g *grammar) NonProductive() []Id {
+	productive := newIdSet()
+	for changed := true; changed; {
+		changed = false
+	Rules:
+		for _, r := range g.rules {
+			if productive.Has(r.Subject()) {
+				continue
+			}
+			for _, id := range r.Definition() {
+				if !g.IsTerminal(id) && !productive.Has(id) {
+					continue Rules
+				}
+			}
+			productive.Add(r.Subject())
+			changed = true
+		}
+	}
+	res := newIdSet()
+	for id := range g.subjectsIndices {
+		if !productive.Has(id) {
+			res.Add(id)
+		}
+	}
+	return res.Ids()
+}
+
+func (g *grammar) LeftRecursion() [][]Id {
+	// A -> B when A can start with B
+	edges := make(map[Id]idSet)
+	for id := range g.subjectsIndices {
+		edges[id] = newIdSet()
+	}
+	sets := g.Sets()
+	for _, r := range g.rules {
+		for _, id := range r.Definition() {
+			if g.IsTerminal(id) {
+				break
+			}
+			edges[r.Subject()].Add(id)
+			if !sets.nullable.Has(id) {
+				break
+			}
+		}
+	}
+
+	var res [][]Id
+	for _, scc := range stronglyConnected(edges) {
+		if len(scc) == 1 && !edges[scc[0]].Has(scc[0]) {
+			continue
+		}
+		res = append(res, shortestCycle(scc[0], edges, newIdSet(scc...)))
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i][0] < res[j][0] })
+	return res
+}
+
+// stronglyConnected returns strongly connected components of the given graph
+// by Tarjan's algorithm. Every component is sorted.
+//
+// https://en.wikipedia.org/wiki/Tarjan%27s_strongly_connected_components_algorithm
+func stronglyConnected(edges map[Id]idSet) [][]Id {
+	var (
+		index   = make(map[Id]int)
+		low     = make(map[Id]int)
+		onStack = newIdSet()
+		stack   []Id
+		res     [][]Id
+		visit   func(id Id)
+	)
+	visit = func(id Id) {
+		index[id] = len(index)
+		low[id] = index[id]
+		stack = append(stack, id)
+		onStack.Add(id)
+
+		for _, to := range edges[id].Ids() {
+			if _, ok := index[to]; !ok {
+				visit(to)
+				if low[to] < low[id] {
+					low[id] = low[to]
+				}
+			} else if onStack.Has(to) && index[to] < low[id] {
+				low[id] = index[to]
+			}
+		}
+
+		if low[id] != index[id] {
+			return
+		}
+		scc := newIdSet()
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack.Remove(top)
+			scc.Add(top)
+			if top == id {
+				break
+			}
+		}
+		res = append(res, scc.Ids())
+	}
+
+	all := newIdSet()
+	for id := range edges {
+		all.Add(id)
+	}
+	for _, id := range all.Ids() {
+		if _, ok := index[id]; !ok {
+			visit(id)
+		}
+	}
+	return res
+}
+
+// shortestCycle returns the shortest path from the given Id back to itself
+// through the given nodes only. The path starts with the given Id and does not
+// repeat it in the end.
+func shortestCycle(from Id, edges map[Id]idSet, within readonlyIdSet) []Id {
+	prev := make(map[Id]Id)
+	for queue := []Id{from}; len(queue) != 0; {
+		id := queue[0]
+		queue = queue[1:]
+		for _, to := range edges[id].Ids() {
+			if !within.Has(to) {
+				continue
+			}
+			if to == from {
+				var path []Id
+				for at := id; at != from; at = prev[at] {
+					path = append(path, at)
+				}
+				path = append(path, from)
+				for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+					path[i], path[j] = path[j], path[i]
+				}
+				return path
+			}
+			if _, ok := prev[to]; ok {
+				continue
+			}
+			prev[to] = id
+			queue = append(queue, to)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package lr0
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestGrammar_Analysis(t *testing.T) {
+	// Goal : Sum $
+	// Sum  : Sum "+" Prod | Prod
+	// Prod : Val "*" Prod | Val
+	// Val  : Sign int
+	// Sign : "-" | ε
+	// Div  : Div "/" Val          -- unreachable and non-productive
+	// Inc  : Ident Inc | ε        -- unreachable
+	// Ident: Inc ident            -- unreachable, left recursion with Inc
+	const (
+		nSign Id = nGoal + 1 + iota
+		nDiv
+		nInc
+		nIdent
+	)
+	g, err := NewGrammar(
+		[]Terminal{
+			NewTerm(tInt, "int").FuncByte(isDigit, bytesToInt),
+			NewTerm(tPlus, `"+"`).Hide().Str("+"),
+			NewTerm(tMinus, `"-"`).Hide().Str("-"),
+			NewTerm(tMul, `"*"`).Hide().Str("*"),
+			NewTerm(tDiv, `"/"`).Hide().Str("/"),
+			NewTerm(tIdent, "ident").Func(matchIdentifier),
+		},
+		[]NonTerminalDefinition{
+			NewNT(nGoal, "Goal").Main().Is(nSum),
+			NewNT(nSum, "Sum").Is(nSum, tPlus, nProd).Do(calc2AnyFirst).Is(nProd),
+			NewNT(nProd, "Prod").Is(nVal, tMul, nProd).Do(calc2AnyFirst).Is(nVal),
+			NewNT(nVal, "Val").Is(nSign, tInt).Do(calc2AnyFirst),
+			NewNT(nSign, "Sign").Is(tMinus).Do(func() int { return -1 }).IsEmpty(),
+			NewNT(nDiv, "Div").Is(nDiv, tDiv, nVal).Do(calc2AnyFirst),
+			NewNT(nInc, "Inc").Is(nIdent, nInc).Do(calc2AnyFirst).IsEmpty(),
+			NewNT(nIdent, "Ident").Is(nInc, tIdent).Do(calc2AnyFirst),
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := func(t *testing.T, name string, got []Id, ids ...Id) {
+		t.Helper()
+		if fmt.Sprint(got) != fmt.Sprint(ids) {
+			t.Errorf("%s: %v, expected %v", name, dumpIds(got, g), dumpIds(ids, g))
+		}
+	}
+
+	expect(t, "terminals", g.Terminals(), tInt, tPlus, tMinus, tMul, tDiv, tIdent)
+	expect(t, "non-terminals", g.NonTerminals(), nVal, nProd, nSum, nGoal, nSign, nDiv, nInc, nIdent)
+	expect(t, "nullable", g.Nullable(), nSign, nInc)
+	if !g.IsNullable(nSign) || g.IsNullable(nVal) {
+		t.Error("IsNullable")
+	}
+
+	expect(t, "FIRST(int)", g.First(tInt), tInt)
+	expect(t, "FIRST(Sum)", g.First(nSum), tInt, tMinus)
+	expect(t, "FIRST(Sign)", g.First(nSign), tMinus)
+	expect(t, "FIRST(Inc)", g.First(nInc), tIdent)
+
+	expect(t, "FOLLOW(Sum)", g.Follow(nSum), EOF, tPlus)
+	expect(t, "FOLLOW(Prod)", g.Follow(nProd), EOF, tPlus)
+	expect(t, "FOLLOW(Val)", g.Follow(nVal), EOF, tPlus, tMul, tDiv)
+	expect(t, "FOLLOW(Sign)", g.Follow(nSign), tInt)
+	expect(t, "FOLLOW(int)", g.Follow(tInt), EOF, tPlus, tMul, tDiv)
+
+	expect(t, "unreachable", g.Unreachable(), nDiv, nInc, nIdent)
+	expect(t, "non-productive", g.NonProductive(), nDiv)
+
+	cycles := g.LeftRecursion()
+	if s := fmt.Sprint(cycles); s != fmt.Sprint([][]Id{{nSum}, {nDiv}, {nInc, nIdent}}) {
+		t.Errorf("left recursion: %v", s)
+	}
+}
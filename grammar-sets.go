@@ -0,0 +1,96 @@
+package lr0
+
+// grammarSets holds nullable and FIRST sets for non-terminals and FOLLOW
+// sets for all symbols of a grammar
+//
+// FOLLOW set can contain tEof which means the symbol can be followed by EOF.
+//
+// https://en.wikipedia.org/wiki/LL_parser#Constructing_an_LL(1)_parsing_table
+type grammarSets struct {
+	g        *grammar
+	nullable idSet
+	first    map[Id]idSet
+	follow   map[Id]idSet
+}
+
+func newGrammarSets(g *grammar) *grammarSets {
+	s := &grammarSets{
+		g:        g,
+		nullable: newIdSet(),
+		first:    make(map[Id]idSet),
+		follow:   make(map[Id]idSet),
+	}
+	for id := range g.subjectsIndices {
+		s.first[id] = newIdSet()
+		s.follow[id] = newIdSet()
+	}
+	for id := range g.terminals {
+		s.follow[id] = newIdSet()
+	}
+	s.calcFirst()
+	s.calcFollow()
+	return s
+}
+
+func (s *grammarSets) calcFirst() {
+	for changed := true; changed; {
+		changed = false
+		for _, r := range s.g.rules {
+			subj := r.Subject()
+			first := s.first[subj]
+			before := first.Count()
+			if s.addFirstOf(first, r.Definition()) && !s.nullable.Has(subj) {
+				s.nullable.Add(subj)
+				changed = true
+			}
+			if first.Count() != before {
+				changed = true
+			}
+		}
+	}
+}
+
+func (s *grammarSets) calcFollow() {
+	for changed := true; changed; {
+		changed = false
+		for _, r := range s.g.rules {
+			def := r.Definition()
+			for i, id := range def {
+				follow, ok := s.follow[id]
+				if !ok {
+					continue
+				}
+				before := follow.Count()
+				if s.addFirstOf(follow, def[i+1:]) {
+					if r.HasEOF() {
+						follow.Add(tEof)
+					}
+					follow.Add(s.follow[r.Subject()].Ids()...)
+				}
+				if follow.Count() != before {
+					changed = true
+				}
+			}
+		}
+	}
+}
+
+// addFirstOf adds FIRST set of the given sequence into `to` and returns
+// whether the whole sequence is nullable
+func (s *grammarSets) addFirstOf(to idSet, ids []Id) bool {
+	for _, id := range ids {
+		first, ok := s.first[id]
+		if !ok {
+			to.Add(id)
+			return false
+		}
+		to.Add(first.Ids()...)
+		if !s.nullable.Has(id) {
+			return false
+		}
+	}
+	return true
+}
+
+// Follow returns FOLLOW set of the given symbol
+func (s *grammarSets) Follow(id Id) readonlyIdSet { return s.follow[id] }
@@ -0,0 +1,106 @@
+package lr0
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestGrammarSets(t *testing.T) {
+	s := testTableItemsetGrammar.Sets()
+	if s != testTableItemsetGrammar.Sets() {
+		t.Error("sets are not cached")
+	}
+	if s.nullable.Count() != 0 {
+		t.Errorf("nullable: %v", s.nullable.Ids())
+	}
+
+	expectSet := func(t *testing.T, name string, got readonlyIdSet, ids ...Id) {
+		t.Helper()
+		if got.Count() != len(ids) {
+			t.Errorf("%s: %v, expected %v", name, got.Ids(), ids)
+			return
+		}
+		for _, id := range ids {
+			if !got.Has(id) {
+				t.Errorf("%s: %v, expected %v", name, got.Ids(), ids)
+				return
+			}
+		}
+	}
+
+	expectSet(t, "FIRST(Goal)", s.first[nGoal], tZero, tOne)
+	expectSet(t, "FIRST(Sum)", s.first[nSum], tZero, tOne)
+	expectSet(t, "FIRST(Val)", s.first[nVal], tZero, tOne)
+
+	expectSet(t, "FOLLOW(Goal)", s.Follow(nGoal))
+	expectSet(t, "FOLLOW(Sum)", s.Follow(nSum), tPlus, tMinus, tEof)
+	expectSet(t, "FOLLOW(Val)", s.Follow(nVal), tPlus, tMinus, tEof)
+}
+
+func TestGrammarSets_Concurrent(t *testing.T) {
+	g := newGrammar(
+		[]Terminal{
+			NewTerm(tInt, "int").FuncByte(isDigit, bytesToInt),
+			NewTerm(tPlus, `"+"`).Hide().Str("+"),
+		},
+		[]NonTerminalDefinition{
+			NewNT(nGoal, "Goal").Main().Is(nSum),
+			NewNT(nSum, "Sum").Is(nSum, tPlus, tInt).Do(calc2IntSum).Is(tInt),
+		},
+	)
+	var wg sync.WaitGroup
+	sets := make([]*grammarSets, 4)
+	for i := range sets {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sets[i] = g.Sets()
+		}(i)
+	}
+	wg.Wait()
+	for _, s := range sets {
+		if s != sets[0] {
+			t.Fatal("sets are calculated many times")
+		}
+	}
+}
+
+func TestGrammarSets_Nullable(t *testing.T) {
+	// Goal : List $
+	// List : List Item | ε
+	// Item : Sign int
+	// Sign : "-" | ε
+	g := newGrammar(
+		[]Terminal{
+			NewTerm(tInt, "int").FuncByte(isDigit, bytesToInt),
+			NewTerm(tMinus, `"-"`).Str("-"),
+		},
+		[]NonTerminalDefinition{
+			NewNT(nGoal, "Goal").Main().Is(nSum),
+			NewNT(nSum, "List").Is(nSum, nProd).Do(calc2AnyFirst).IsEmpty(),
+			NewNT(nProd, "Item").Is(nVal, tInt).Do(calc2AnyFirst),
+			NewNT(nVal, "Sign").Is(tMinus).IsEmpty(),
+		},
+	)
+	s := g.Sets()
+
+	if n := s.nullable; n.Count() != 3 || !n.Has(nGoal) || !n.Has(nSum) || !n.Has(nVal) {
+		t.Errorf("nullable: %v", n.Ids())
+	}
+	if f := s.first[nSum]; f.Count() != 2 || !f.Has(tInt) || !f.Has(tMinus) {
+		t.Errorf("FIRST(List): %v", f.Ids())
+	}
+	if f := s.first[nVal]; f.Count() != 1 || !f.Has(tMinus) {
+		t.Errorf("FIRST(Sign): %v", f.Ids())
+	}
+	if f := s.Follow(nSum); f.Count() != 3 || !f.Has(tInt) || !f.Has(tMinus) || !f.Has(tEof) {
+		t.Errorf("FOLLOW(List): %v", f.Ids())
+	}
+	if f := s.Follow(nVal); f.Count() != 1 || !f.Has(tInt) {
+		t.Errorf("FOLLOW(Sign): %v", f.Ids())
+	}
+
+	if r := g.RulesFor(nVal)[1].String(); r != "Sign : ε" {
+		t.Errorf("empty rule string: %q", r)
+	}
+}
@@ -0,0 +1,199 @@
+package lr0
+
+import (
+	"reflect"
+
+	"github.com/pkg/errors"
+)
+
+// typedSymbol is a symbol which can declare type of its values
+type typedSymbol interface {
+	valueType() reflect.Type
+}
+
+// symbolType is a state of knowledge about type of symbol values while
+// inferring types
+type symbolType struct {
+	// t is the type when it's known
+	t reflect.Type
+	// unknown is set when the type cannot be known or rules give different
+	// types
+	unknown bool
+}
+
+func (a symbolType) isPending() bool { return a.t == nil && !a.unknown }
+
+// join combines types of alternative values
+func (a symbolType) join(b symbolType) symbolType {
+	switch {
+	case a.unknown || b.isPending():
+		return a
+	case b.unknown || a.isPending():
+		return b
+	case a.t == b.t:
+		return a
+	default:
+		return symbolType{unknown: true}
+	}
+}
+
+// checkTypes checks statically that values of symbols can be passed to Do
+// handlers of rules
+//
+// Types of Terminals are known from their factories or Type declarations.
+// Types of non-terminals are declared by Type or inferred from rules when
+// all of them give the same type. Nothing is checked for unknown types and
+// for values of interface types since actual values can fit.
+func checkTypes(g *grammar) []error {
+	types := make(map[Id]symbolType)
+	for id, t := range g.lexer.terminals {
+		types[id] = declaredType(t)
+	}
+	declared := make(map[Id]bool)
+	for id, s := range g.nonTerm {
+		if st := declaredType(s); st.t != nil {
+			types[id] = st
+			declared[id] = true
+		}
+	}
+
+	symType := func(id Id) symbolType {
+		if s, ok := g.getEbnfSymbol(id); ok {
+			return ebnfType(g, s, types)
+		}
+		return types[id]
+	}
+	// types are inferred until nothing changes, since non-terminals refer
+	// each other recursively
+	for changed := true; changed; {
+		changed = false
+		for id := range g.nonTerm {
+			if declared[id] {
+				continue
+			}
+			if _, ok := g.getEbnfSymbol(id); ok {
+				continue
+			}
+			var st symbolType
+			for _, r := range g.subjectsIndices[id] {
+				st = st.join(ruleType(g.rules[r], symType))
+			}
+			if st != types[id] {
+				types[id] = st
+				changed = true
+			}
+		}
+	}
+
+	var errs []error
+	for _, r := range g.rules {
+		rr, ok := r.(*rule)
+		if !ok || rr.calc == nil {
+			continue
+		}
+		if _, ok := g.getEbnfSymbol(rr.subject); ok {
+			continue
+		}
+		if declared[rr.subject] {
+			want := types[rr.subject].t
+			if got := ruleType(rr, symType); !isAssignableType(got, want) {
+				errs = append(errs, errors.Wrapf(
+					ErrDefine,
+					"rule for %s: %s: value of type %s cannot be used as %s",
+					dumpId(rr.subject, g), rr, got.t, want,
+				))
+			}
+		}
+		if rr.argTypes == nil {
+			continue
+		}
+		arg := 0
+		if rr.span {
+			arg = 1
+		}
+		for i, id := range rr.definition {
+			if rr.IsHidden(i) {
+				continue
+			}
+			if got := symType(id); !isAssignableType(got, rr.argTypes[arg]) {
+				errs = append(errs, errors.Wrapf(
+					ErrDefine,
+					"rule for %s: %s: argument %d of type %s cannot be assigned with %s of type %s",
+					dumpId(rr.subject, g), rr, arg+1, rr.argTypes[arg], dumpId(id, g), got.t,
+				))
+			}
+			arg++
+		}
+	}
+	return errs
+}
+
+func declaredType(s Symbol) symbolType {
+	ts, ok := s.(typedSymbol)
+	if !ok || ts.valueType() == nil {
+		return symbolType{unknown: true}
+	}
+	return symbolType{t: ts.valueType()}
+}
+
+// ruleType returns type of values given by the Rule
+func ruleType(r Rule, symType func(Id) symbolType) symbolType {
+	rr, ok := r.(*rule)
+	if !ok || rr.calc == nil {
+		return symbolType{unknown: true}
+	}
+	if rr.resultType != nil {
+		return symbolType{t: rr.resultType}
+	}
+	var visible []Id
+	for i, id := range rr.definition {
+		if !rr.IsHidden(i) {
+			visible = append(visible, id)
+		}
+	}
+	switch len(visible) {
+	case 0:
+		// nil fits anything
+		return symbolType{}
+	case 1:
+		return symType(visible[0])
+	default:
+		return symbolType{unknown: true}
+	}
+}
+
+// ebnfType returns type of values of EBNF symbol. It's `[]T` for repetitions
+// of T, and unknown for Group.
+func ebnfType(g *grammar, s ebnfSymbol, types map[Id]symbolType) symbolType {
+	item := types[s.ids[0]]
+	if sub, ok := g.getEbnfSymbol(s.ids[0]); ok {
+		item = ebnfType(g, sub, types)
+	}
+	if item.t == nil {
+		return item
+	}
+	switch s.kind {
+	case ebnfOptional:
+		return item
+	case ebnfZeroOrMore, ebnfOneOrMore, ebnfList:
+		return symbolType{t: reflect.SliceOf(item.t)}
+	default:
+		return symbolType{unknown: true}
+	}
+}
+
+// isAssignableType checks if a value of the given symbolType can be used as
+// a value of type `to`. Unknown types and interfaces are considered
+// assignable. Slices from EBNF repetitions are converted item by item.
+func isAssignableType(from symbolType, to reflect.Type) bool {
+	t := from.t
+	for {
+		if t == nil || t.Kind() == reflect.Interface || t.AssignableTo(to) {
+			return true
+		}
+		if t.Kind() != reflect.Slice || to.Kind() != reflect.Slice {
+			return false
+		}
+		t, to = t.Elem(), to.Elem()
+	}
+}
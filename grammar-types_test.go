@@ -0,0 +1,123 @@
+package lr0
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestCheckTypes(t *testing.T) {
+	terminals := func(ids ...Id) []Terminal {
+		all := map[Id]Terminal{
+			tInt:   NewTerm(tInt, "int").FuncByte(isDigit, bytesToInt),
+			tPlus:  NewTerm(tPlus, `"+"`).Hide().Str("+"),
+			tMinus: NewTerm(tMinus, `"-"`).Str("-"),
+			tIdent: NewTerm(tIdent, "ident").Type(reflect.TypeOf("")).Func(matchIdentifier),
+		}
+		res := make([]Terminal, 0, len(ids))
+		for _, id := range ids {
+			res = append(res, all[id])
+		}
+		return res
+	}
+	type testCase struct {
+		name  string
+		terms []Id
+		rules []NonTerminalDefinition
+		err   string
+	}
+	for _, c := range []testCase{
+		{
+			name:  "inferred through recursion",
+			terms: []Id{tInt, tPlus, tMinus, tIdent},
+			rules: []NonTerminalDefinition{
+				NewNT(nGoal, "Goal").Main().Is(nSum),
+				NewNT(nSum, "Sum").
+					Is(nSum, tPlus, nVal).Do(calc2IntSum).
+					Is(nSum, tMinus, nVal).Do(Do3(func(a int, _ string, b int) int { return a - b })).
+					Is(nVal),
+				NewNT(nVal, "Val").Is(tInt).Is(tIdent, tPlus, nSum).Do(func(_ string, v int) int { return v }),
+			},
+		},
+		{
+			name:  "terminal",
+			terms: []Id{tInt, tMinus},
+			rules: []NonTerminalDefinition{
+				NewNT(nGoal, "Goal").Main().Is(nSum),
+				NewNT(nSum, "Sum").Is(tInt, tMinus).Do(func(a, b int) int { return a - b }),
+			},
+			err: `rule for Sum: Sum : int "-": argument 2 of type int cannot be assigned with "-" of type string: invalid definition`,
+		},
+		{
+			name:  "non-terminal",
+			terms: []Id{tInt, tPlus},
+			rules: []NonTerminalDefinition{
+				NewNT(nGoal, "Goal").Main().Is(nSum),
+				NewNT(nSum, "Sum").Is(nVal, tPlus, nVal).Do(Do2(func(a, b string) string { return a + b })),
+				NewNT(nVal, "Val").Is(tInt),
+			},
+			err: `rule for Sum: Sum : Val "+" Val: argument 1 of type string cannot be assigned with Val of type int: invalid definition
+rule for Sum: Sum : Val "+" Val: argument 2 of type string cannot be assigned with Val of type int: invalid definition`,
+		},
+		{
+			name:  "declared",
+			terms: []Id{tInt, tPlus, tIdent},
+			rules: []NonTerminalDefinition{
+				NewNT(nGoal, "Goal").Main().Is(nSum),
+				NewNT(nSum, "Sum").Type(reflect.TypeOf(0)).
+					Is(nSum, tPlus, nVal).Do(calc2IntSum).
+					Is(tIdent),
+				NewNT(nVal, "Val").Is(tInt),
+			},
+			err: `rule for Sum: Sum : ident: value of type string cannot be used as int: invalid definition`,
+		},
+		{
+			name:  "different types are unknown",
+			terms: []Id{tInt, tPlus, tIdent},
+			rules: []NonTerminalDefinition{
+				NewNT(nGoal, "Goal").Main().Is(nSum),
+				NewNT(nSum, "Sum").Is(nVal, tPlus, nVal).Do(calc2IntSum),
+				NewNT(nVal, "Val").Is(tInt).Is(tIdent),
+			},
+		},
+		{
+			name:  "interface",
+			terms: []Id{tInt, tPlus, tMinus},
+			rules: []NonTerminalDefinition{
+				NewNT(nGoal, "Goal").Main().Is(nSum),
+				NewNT(nSum, "Sum").Is(nVal, tPlus, nVal).Do(calc2IntSum),
+				NewNT(nVal, "Val").Is(tInt, tMinus).Do(calc2AnyFirst),
+			},
+		},
+		{
+			name:  "EBNF",
+			terms: []Id{tInt, tPlus, tMinus, tIdent},
+			rules: []NonTerminalDefinition{
+				NewNT(nGoal, "Goal").Main().Is(nSum),
+				NewNT(nSum, "Sum").
+					Is(Optional(tIdent), List(tInt, tPlus), ZeroOrMore(tMinus)).
+					Do(func(s string, list []int, minuses []int) int { return 0 }),
+			},
+			err: `rule for Sum: Sum : ident? int ("+" int)* "-"*: argument 3 of type []int cannot be assigned with "-"* of type []string: invalid definition`,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := NewGrammar(terminals(c.terms...), c.rules)
+			if c.err == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrDefine) {
+				t.Fatal("another error:", err)
+			}
+			if !strings.Contains(fmt.Sprint(err), c.err) {
+				t.Errorf("wrong error:\n%s\nexpected:\n%s", err, c.err)
+			}
+		})
+	}
+}
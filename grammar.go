@@ -2,20 +2,70 @@ package lr0
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/pkg/errors"
+
+	"github.com/vovan-ve/go-lr0-parser/internal/helpers"
 )
 
-//// Grammar defines full grammar how to parse an input stream
-//type Grammar interface {
-//	Lexer
-//	RulesCount() int
-//	Rule(index int) Rule
-//	// MainRule returns main rule, the one with EOF flag
-//	MainRule() Rule
-//	// RulesFor returns set of rules for the given subject
-//	RulesFor(id Id) []Rule
-//}
+// Grammar defines full grammar how to parse an input stream
+//
+// It's read-only. Besides rules, it provides analysis useful to check the
+// grammar or to generate docs. Sets of Id are returned as sorted slices.
+type Grammar interface {
+	NamedHiddenRegistry
+	// IsTerminal returns true if the given Id is one of defined Terminal
+	IsTerminal(id Id) bool
+	// Terminals returns Id of all defined Terminals
+	Terminals() []Id
+	// NonTerminals returns Id of all defined non-terminals
+	NonTerminals() []Id
+	RulesCount() int
+	Rule(index int) Rule
+	// MainRule returns the first main rule, the one with EOF flag
+	MainRule() Rule
+	// MainRules returns all main rules in definition order. Every one is an
+	// entry point to parse, see Parser.ParseStart.
+	MainRules() []Rule
+	// RulesFor returns set of rules for the given subject, or nil for
+	// Terminal or unknown Id
+	RulesFor(id Id) []Rule
+
+	// IsNullable returns true if the given non-terminal can be empty
+	IsNullable(id Id) bool
+	// Nullable returns all non-terminals which can be empty
+	Nullable() []Id
+	// First returns FIRST set of the given symbol: terminals which can start
+	// it. FIRST set of a Terminal is the Terminal itself.
+	First(id Id) []Id
+	// Follow returns FOLLOW set of the given symbol: terminals which can
+	// follow it. It can contain EOF.
+	Follow(id Id) []Id
+	// Unreachable returns non-terminals which cannot be reached from any
+	// main rule
+	Unreachable() []Id
+	// NonProductive returns non-terminals which can never be reduced since
+	// every their rule refers to itself endlessly
+	NonProductive() []Id
+	// LeftRecursion returns left-recursive cycles. A cycle is a list of
+	// non-terminals where every one can start with the next one, and the last
+	// one can start with the first one. So `[Sum]` is direct left recursion
+	// like `Sum : Sum "+" Val`.
+	LeftRecursion() [][]Id
+}
+
+// NewGrammar creates new Grammar for analysis
+//
+// terminals and rules are the same as for New. Definition mistakes are
+// returned like NewE does, but without conflicts since no table is built.
+func NewGrammar(terminals []Terminal, rules []NonTerminalDefinition) (Grammar, error) {
+	g, errs := newGrammarE(terminals, rules)
+	if len(errs) != 0 {
+		return nil, joinDefinitionErrors(errs)
+	}
+	return g, nil
+}
 
 // newGrammar creates new Grammar
 //
@@ -27,12 +77,24 @@ import (
 // - Every Id in every rule definition must exist either in Terminals or
 // in Rules Subject
 //
-// - Exactly one Rule must have EOF flag - this is Main Rule
+// - At least one Rule must have EOF flag - this is Main Rule. Main Rules
+// must have different subjects.
 func newGrammar(terminals []Terminal, nonTerminals []NonTerminalDefinition) *grammar {
+	gr, errs := newGrammarE(terminals, nonTerminals)
+	if len(errs) != 0 {
+		panic(errs[0])
+	}
+	return gr
+}
+
+// newGrammarE creates new Grammar like newGrammar does, but returns all
+// definition errors found instead of panic
+func newGrammarE(terminals []Terminal, nonTerminals []NonTerminalDefinition) (*grammar, []error) {
 	var (
-		l           = newLexer(terminals...)
+		l, errs     = newLexerE(terminals...)
 		nonTerm     = make(map[Id]Symbol)
-		mainS       Symbol
+		mainS       = make(map[Id]Symbol)
+		failedNT    bool
 		ruleIndex   int
 		si          = make(map[Id][]int)
 		furtherNTAt = make(map[Id]string)
@@ -43,29 +105,23 @@ func newGrammar(terminals []Terminal, nonTerminals []NonTerminalDefinition) *gra
 		lexer:           l,
 		nonTerm:         nonTerm,
 		rules:           make([]Rule, 0),
-		mainIndex:       -1,
 		subjectsIndices: si,
+		ebnf:            make(map[Id]ebnfSymbol),
+		ebnfIds:         make(map[Id]Id),
 	}
 
-	for _, ntDef := range nonTerminals {
+	var ebnfIds []Id
+	addRules := func(ntDef Symbol, rules []Rule) {
 		subjId := ntDef.Id()
-		nonTerm[subjId] = ntDef
-		delete(furtherNTAt, subjId)
-
-		if subjId == InvalidId {
-			panic(errors.Wrapf(ErrDefine, "Non-Terminal %s id is zero", dumpSymbol(ntDef)))
-		}
-		if l.IsTerminal(subjId) {
-			panic(errors.Wrapf(ErrDefine, "Non-Terminal %s is Terminal", dumpSymbol(ntDef)))
-		}
-
-		for ri, r := range ntDef.GetRules(gr) {
+		for ri, r := range rules {
+			r = gr.withEbnfIds(r)
 			if r.HasEOF() {
-				if mainS != nil {
-					panic(errors.Wrapf(ErrDefine, "another rule %s has Main flag too, previous was %s", dumpSymbol(ntDef), dumpSymbol(mainS)))
+				if prev, ok := mainS[subjId]; ok {
+					errs = append(errs, errors.Wrapf(ErrDefine, "another rule %s has Main flag too, previous was %s", dumpSymbol(ntDef), dumpSymbol(prev)))
+				} else {
+					mainS[subjId] = ntDef
+					gr.mainIndices = append(gr.mainIndices, ruleIndex)
 				}
-				mainS = ntDef
-				gr.mainIndex = ruleIndex
 			}
 
 			si[subjId] = append(si[subjId], ruleIndex)
@@ -75,6 +131,12 @@ func newGrammar(terminals []Terminal, nonTerminals []NonTerminalDefinition) *gra
 			ruleIndex++
 
 			for i, id := range r.Definition() {
+				// Error pseudo-terminal is defined when used
+				if id == tError {
+					l.terminals[id] = metaError
+					usedT[id] = struct{}{}
+					continue
+				}
 				// defined Terminal - ok
 				if l.IsTerminal(id) {
 					usedT[id] = struct{}{}
@@ -88,29 +150,75 @@ func newGrammar(terminals []Terminal, nonTerminals []NonTerminalDefinition) *gra
 				if _, ok := furtherNTAt[id]; ok {
 					continue
 				}
+				// EBNF symbol will be defined later
+				if _, ok := gr.getEbnfSymbol(id); ok {
+					si[id] = nil
+					ebnfIds = append(ebnfIds, id)
+					continue
+				}
 				// seeing this non-terminal first time
 				furtherNTAt[id] = fmt.Sprintf("#%d in NT %s rules[%d] (%s) definitions[%d]", id, dumpSymbol(ntDef), ri, r, i)
 			}
 		}
 	}
 
+	for _, ntDef := range nonTerminals {
+		subjId := ntDef.Id()
+		nonTerm[subjId] = ntDef
+		delete(furtherNTAt, subjId)
+
+		if subjId == InvalidId {
+			errs = append(errs, errors.Wrapf(ErrDefine, "Non-Terminal %s id is zero", dumpSymbol(ntDef)))
+			continue
+		}
+		if l.IsTerminal(subjId) {
+			errs = append(errs, errors.Wrapf(ErrDefine, "Non-Terminal %s is Terminal", dumpSymbol(ntDef)))
+			continue
+		}
+
+		var rules []Rule
+		if nt, ok := ntDef.(*NonTerminal); ok {
+			// rules are checked even with errors
+			var ntErrs []error
+			rules, ntErrs = nt.getRules(gr)
+			errs = append(errs, ntErrs...)
+		} else if err := catchDefine(func() { rules = ntDef.GetRules(gr) }); err != nil {
+			errs = appendDefinitionErrors(errs, err)
+			failedNT = true
+			// it's defined anyway to not report it as undefined
+			si[subjId] = si[subjId]
+			continue
+		}
+
+		addRules(ntDef, rules)
+	}
+
+	// EBNF symbols get rules after all non-terminals are known, since names
+	// of the symbols refer them
+	for i := 0; i < len(ebnfIds); i++ {
+		id := ebnfIds[i]
+		s, _ := gr.getEbnfSymbol(id)
+		nt := &ebnfNonTerminal{id: id, name: ebnfName(s, gr)}
+		nonTerm[id] = nt
+		addRules(nt, ebnfRules(id, s, gr))
+	}
+
 	if len(furtherNTAt) != 0 {
 		msg := "undefined non-terminals without rules:\n"
-		for _, at := range furtherNTAt {
-			msg += "- " + at + "\n"
+		for _, at := range helpers.MapSortedInt(furtherNTAt) {
+			msg += "- " + at.V + "\n"
 		}
-		panic(errors.Wrap(ErrDefine, msg))
+		errs = append(errs, errors.Wrap(ErrDefine, msg))
 	}
-	if gr.mainIndex == -1 {
-		panic(errors.Wrap(ErrDefine, "no main rule with EOF flag"))
+	// main rule could be in failed non-terminal
+	if len(gr.mainIndices) == 0 && !failedNT {
+		errs = append(errs, errors.Wrap(ErrDefine, "no main rule with EOF flag"))
 	}
-	if len(usedT) != len(terminals) {
+	// terminals could be used in failed non-terminal
+	if len(usedT) != len(l.terminals) && !failedNT {
 		msg := "following Terminals are not used in any Rule:\n"
 		bad := false
-		for _, t := range terminals {
-			if t.Id() < 0 {
-				continue
-			}
+		for _, t := range l.list {
 			if _, ok := usedT[t.Id()]; ok {
 				continue
 			}
@@ -118,19 +226,26 @@ func newGrammar(terminals []Terminal, nonTerminals []NonTerminalDefinition) *gra
 			bad = true
 		}
 		if bad {
-			panic(errors.Wrap(ErrDefine, msg))
+			errs = append(errs, errors.Wrap(ErrDefine, msg))
 		}
 	}
+	errs = append(errs, checkTypes(gr)...)
 
-	return gr
+	return gr, errs
 }
 
 type grammar struct {
 	*lexer
 	nonTerm         map[Id]Symbol
 	rules           []Rule
-	mainIndex       int
+	mainIndices     []int
 	subjectsIndices map[Id][]int
+	setsOnce        sync.Once
+	sets            *grammarSets
+	// ebnf are EBNF symbols by Id, and ebnfIds are their Ids by tokens from
+	// Optional and others
+	ebnf    map[Id]ebnfSymbol
+	ebnfIds map[Id]Id
 }
 
 func (g *grammar) SymbolName(id Id) string {
@@ -143,20 +258,32 @@ func (g *grammar) SymbolName(id Id) string {
 	return ""
 }
 
+// Sets returns nullable, FIRST and FOLLOW sets calculated once on demand. It's
+// safe for concurrent use, since Grammar is shared by Parser.
+func (g *grammar) Sets() *grammarSets {
+	g.setsOnce.Do(func() { g.sets = newGrammarSets(g) })
+	return g.sets
+}
+
 func (g *grammar) RulesCount() int     { return len(g.rules) }
 func (g *grammar) Rule(index int) Rule { return g.rules[index] }
 
 func (g *grammar) MainRule() Rule {
-	return g.rules[g.mainIndex]
+	return g.rules[g.mainIndices[0]]
+}
+
+func (g *grammar) MainRules() []Rule {
+	ret := make([]Rule, 0, len(g.mainIndices))
+	for _, idx := range g.mainIndices {
+		ret = append(ret, g.rules[idx])
+	}
+	return ret
 }
 
 func (g *grammar) RulesFor(id Id) []Rule {
 	indices, ok := g.subjectsIndices[id]
 	if !ok {
-		if g.IsTerminal(id) {
-			panic(errors.Wrapf(ErrDefine, "no rule - %s is Terminal", dumpId(id, g)))
-		}
-		panic(errors.Wrapf(ErrDefine, "no rule for #%d", id))
+		return nil
 	}
 	ret := make([]Rule, 0, len(indices))
 	for _, idx := range indices {
@@ -165,24 +165,38 @@ func TestGrammar(t *testing.T) {
 			t.Fatalf("incorrect rules for nGoal: %#v", rg)
 		}
 
-		t.Run("panic: for terminal", func(t *testing.T) {
-			defer testutils.ExpectPanicError(t, ErrDefine, func(t *testing.T, err error) {
-				if err.Error() != "no rule - integer is Terminal: invalid definition" {
-					t.Fatal("wrong error message:", err)
-				}
-			})
-
-			g.RulesFor(tInt)
-		})
-
-		t.Run("panic: unknown symbol", func(t *testing.T) {
-			defer testutils.ExpectPanicError(t, ErrDefine, func(t *testing.T, err error) {
-				if err.Error() != "no rule for #6: invalid definition" {
-					t.Fatal("wrong error message:", err)
-				}
-			})
-
-			g.RulesFor(tMul)
-		})
+		if rs := g.RulesFor(tInt); rs != nil {
+			t.Errorf("rules for terminal: %v", rs)
+		}
+		if rs := g.RulesFor(tMul); rs != nil {
+			t.Errorf("rules for unknown symbol: %v", rs)
+		}
 	})
 }
+
+func TestGrammar_MainRules(t *testing.T) {
+	const nGoalVal = nGoal + 1
+	g := newGrammar(
+		[]Terminal{
+			NewTerm(tInt, "int").FuncByte(isDigit, bytesToInt),
+			NewTerm(tPlus, `"+"`).Hide().Str("+"),
+			NewTerm(tMinus, `"-"`).Hide().Str("-"),
+		},
+		[]NonTerminalDefinition{
+			NewNT(nGoal, "Goal").Main().Is(nSum),
+			NewNT(nGoalVal, "GoalVal").Main().Is(tMinus, nVal).Do(func(v int) int { return -v }),
+			NewNT(nSum, "Sum").Is(nSum, tPlus, tInt).Do(calc2IntSum).Is(tInt),
+			NewNT(nVal, "Val").Is(tInt),
+		},
+	)
+	rules := g.MainRules()
+	if len(rules) != 2 || rules[0].Subject() != nGoal || rules[1].Subject() != nGoalVal {
+		t.Fatalf("main rules: %v", rules)
+	}
+	if g.MainRule() != rules[0] {
+		t.Errorf("main rule: %v", g.MainRule())
+	}
+	if u := g.Unreachable(); len(u) != 0 {
+		t.Errorf("unreachable: %v", u)
+	}
+}
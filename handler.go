@@ -0,0 +1,176 @@
+package lr0
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Handler is a Do handler made by Do0, Do1, etc. helpers. It calls the
+// wrapped func without reflection. A value of wrong type for an argument
+// causes ErrParse error instead of panic.
+//
+//	NewNT(nSum, "Sum").
+//		Is(nSum, tPlus, nVal).Do(Do2(func(a, b int) int { return a + b }))
+type Handler struct {
+	args int
+	calc calcFunc
+	// in and out are types of arguments and result to check grammar
+	in  []reflect.Type
+	out reflect.Type
+}
+
+// Do0 makes Handler of func without arguments
+func Do0[R any](fn func() R) Handler {
+	if fn == nil {
+		return Handler{}
+	}
+	return Do0E(func() (R, error) { return fn(), nil })
+}
+
+// Do0E makes Handler of func without arguments which can return error
+func Do0E[R any](fn func() (R, error)) Handler {
+	if fn == nil {
+		return Handler{}
+	}
+	return Handler{args: 0, out: typeOf[R](), calc: func([]any) (any, error) {
+		return fn()
+	}}
+}
+
+// Do1 makes Handler of func with one argument
+func Do1[A, R any](fn func(A) R) Handler {
+	if fn == nil {
+		return Handler{}
+	}
+	return Do1E(func(a A) (R, error) { return fn(a), nil })
+}
+
+// Do1E makes Handler of func with one argument which can return error
+func Do1E[A, R any](fn func(A) (R, error)) Handler {
+	if fn == nil {
+		return Handler{}
+	}
+	return Handler{args: 1, in: []reflect.Type{typeOf[A]()}, out: typeOf[R](), calc: func(v []any) (any, error) {
+		a, err := handlerArg[A](v, 0)
+		if err != nil {
+			return nil, err
+		}
+		return fn(a)
+	}}
+}
+
+// Do2 makes Handler of func with two arguments
+func Do2[A, B, R any](fn func(A, B) R) Handler {
+	if fn == nil {
+		return Handler{}
+	}
+	return Do2E(func(a A, b B) (R, error) { return fn(a, b), nil })
+}
+
+// Do2E makes Handler of func with two arguments which can return error
+func Do2E[A, B, R any](fn func(A, B) (R, error)) Handler {
+	if fn == nil {
+		return Handler{}
+	}
+	return Handler{args: 2, in: []reflect.Type{typeOf[A](), typeOf[B]()}, out: typeOf[R](), calc: func(v []any) (any, error) {
+		a, err := handlerArg[A](v, 0)
+		if err != nil {
+			return nil, err
+		}
+		b, err := handlerArg[B](v, 1)
+		if err != nil {
+			return nil, err
+		}
+		return fn(a, b)
+	}}
+}
+
+// Do3 makes Handler of func with three arguments
+func Do3[A, B, C, R any](fn func(A, B, C) R) Handler {
+	if fn == nil {
+		return Handler{}
+	}
+	return Do3E(func(a A, b B, c C) (R, error) { return fn(a, b, c), nil })
+}
+
+// Do3E makes Handler of func with three arguments which can return error
+func Do3E[A, B, C, R any](fn func(A, B, C) (R, error)) Handler {
+	if fn == nil {
+		return Handler{}
+	}
+	return Handler{args: 3, in: []reflect.Type{typeOf[A](), typeOf[B](), typeOf[C]()}, out: typeOf[R](), calc: func(v []any) (any, error) {
+		a, err := handlerArg[A](v, 0)
+		if err != nil {
+			return nil, err
+		}
+		b, err := handlerArg[B](v, 1)
+		if err != nil {
+			return nil, err
+		}
+		c, err := handlerArg[C](v, 2)
+		if err != nil {
+			return nil, err
+		}
+		return fn(a, b, c)
+	}}
+}
+
+// Do4 makes Handler of func with four arguments
+func Do4[A, B, C, D, R any](fn func(A, B, C, D) R) Handler {
+	if fn == nil {
+		return Handler{}
+	}
+	return Do4E(func(a A, b B, c C, d D) (R, error) { return fn(a, b, c, d), nil })
+}
+
+// Do4E makes Handler of func with four arguments which can return error
+func Do4E[A, B, C, D, R any](fn func(A, B, C, D) (R, error)) Handler {
+	if fn == nil {
+		return Handler{}
+	}
+	return Handler{args: 4, in: []reflect.Type{typeOf[A](), typeOf[B](), typeOf[C](), typeOf[D]()}, out: typeOf[R](), calc: func(v []any) (any, error) {
+		a, err := handlerArg[A](v, 0)
+		if err != nil {
+			return nil, err
+		}
+		b, err := handlerArg[B](v, 1)
+		if err != nil {
+			return nil, err
+		}
+		c, err := handlerArg[C](v, 2)
+		if err != nil {
+			return nil, err
+		}
+		d, err := handlerArg[D](v, 3)
+		if err != nil {
+			return nil, err
+		}
+		return fn(a, b, c, d)
+	}}
+}
+
+// handlerArg returns value with the given index as T. The nil becomes zero
+// value. Reflection is used only to convert `[]any` from ZeroOrMore and
+// similar symbols to a slice and to describe an error.
+func handlerArg[T any](v []any, i int) (T, error) {
+	var zero T
+	switch a := v[i].(type) {
+	case nil:
+		return zero, nil
+	case T:
+		return a, nil
+	case []any:
+		if res, ok := convertList(a, typeOf[T]()); ok {
+			return res.Interface().(T), nil
+		}
+	}
+	return zero, NewParseError(fmt.Sprintf(
+		"argument %d of type %s cannot be assigned with value of type %T",
+		i+1,
+		typeOf[T](),
+		v[i],
+	))
+}
+
+// typeOf returns reflect.Type of T, including interface types
+func typeOf[T any]() reflect.Type { return reflect.TypeOf((*T)(nil)).Elem() }
@@ -0,0 +1,83 @@
+package lr0
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/vovan-ve/go-lr0-parser/internal/testutils"
+)
+
+func TestHandler(t *testing.T) {
+	t.Run("Do0", func(t *testing.T) {
+		v, err := newCalcFunc(Do0(func() int { return 42 }), 0)(nil)
+		if err != nil || v != 42 {
+			t.Errorf("result is %#v, %v", v, err)
+		}
+	})
+	t.Run("Do2", func(t *testing.T) {
+		v, err := newCalcFunc(Do2(strings.Repeat), 2)([]any{"ab", 3})
+		if err != nil || v != "ababab" {
+			t.Errorf("result is %#v, %v", v, err)
+		}
+	})
+	t.Run("Do3E", func(t *testing.T) {
+		h := newCalcFunc(Do3E(func(a int, s string, b byte) (string, error) {
+			if a < 0 {
+				return "", io.EOF
+			}
+			return strings.Repeat(s, a) + string(b), nil
+		}), 3)
+		if _, err := h([]any{-1, "", byte('x')}); err != io.EOF {
+			t.Errorf("err is %#v", err)
+		}
+		v, err := h([]any{2, "ab", byte('x')})
+		if err != nil || v != "ababx" {
+			t.Errorf("result is %#v, %v", v, err)
+		}
+	})
+	t.Run("Do4", func(t *testing.T) {
+		v, err := newCalcFunc(Do4(func(a, b, c, d int) int { return a + b + c + d }), 4)([]any{1, 2, 3, 4})
+		if err != nil || v != 10 {
+			t.Errorf("result is %#v, %v", v, err)
+		}
+	})
+	t.Run("nil and lists", func(t *testing.T) {
+		h := newCalcFunc(Do2(func(s *string, list [][]int) int {
+			if s != nil {
+				return -1
+			}
+			return len(list) + len(list[1])
+		}), 2)
+		v, err := h([]any{nil, []any{[]any{1}, []any{2, 3}}})
+		if err != nil || v != 4 {
+			t.Errorf("result is %#v, %v", v, err)
+		}
+	})
+	t.Run("nil func", func(t *testing.T) {
+		v, err := newCalcFunc(Do1[int, int](nil), 1)([]any{42})
+		if err != nil || v != 42 {
+			t.Errorf("result is %#v, %v", v, err)
+		}
+	})
+	t.Run("type mismatch", func(t *testing.T) {
+		h := newCalcFunc(Do2(func(a int, b string) string { return b }), 2)
+		_, err := h([]any{1, 2})
+		if !errors.Is(err, ErrParse) {
+			t.Fatalf("err is %#v", err)
+		}
+		const expected = "argument 2 of type string cannot be assigned with value of type int: parse error"
+		if err.Error() != expected {
+			t.Errorf("err is %q", err)
+		}
+		_, err = h([]any{1, []any{"a"}})
+		if !errors.Is(err, ErrParse) {
+			t.Fatalf("err is %#v", err)
+		}
+	})
+	t.Run("args count", func(t *testing.T) {
+		defer testutils.ExpectPanicError(t, ErrDefine)
+		newCalcFunc(Do2(func(a, b int) int { return a + b }), 3)
+	})
+}
@@ -2,6 +2,7 @@ package lr0
 
 import (
 	"fmt"
+	"sort"
 )
 
 func dumpSymbol(s Symbol) string {
@@ -12,15 +13,31 @@ func dumpSymbol(s Symbol) string {
 }
 
 func dumpId(id Id, r SymbolRegistry) string {
+	if id == tEof {
+		return "$"
+	}
 	if s := r.SymbolName(id); s != "" {
 		return s
 	}
 	return fmt.Sprintf("#%v", id)
 }
 
+func dumpIds(ids []Id, r SymbolRegistry) string {
+	s := ""
+	for i, id := range ids {
+		if i > 0 {
+			s += " "
+		}
+		s += dumpId(id, r)
+	}
+	return s
+}
+
 type readonlyIdSet interface {
 	Count() int
 	Has(id Id) bool
+	// Ids returns sorted slice of all Id in the set
+	Ids() []Id
 
 	//IsEmpty() bool
 	//ForEach(fn func(Id))
@@ -53,3 +70,12 @@ func (s idSet) Has(id Id) bool {
 	_, ok := s[id]
 	return ok
 }
+
+func (s idSet) Ids() []Id {
+	res := make([]Id, 0, len(s))
+	for id := range s {
+		res = append(res, id)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i] < res[j] })
+	return res
+}
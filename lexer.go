@@ -37,7 +37,20 @@ type lexer struct {
 }
 
 // newLexer creates a new empty Configurable
+//
+// Definition errors are reported by panic ErrDefine.
 func newLexer(t ...Terminal) *lexer {
+	l, errs := newLexerE(t...)
+	if len(errs) != 0 {
+		panic(errs[0])
+	}
+	return l
+}
+
+// newLexerE creates a new empty Configurable and returns all definition
+// errors found
+func newLexerE(t ...Terminal) (*lexer, []error) {
+	var errs []error
 	l := &lexer{
 		list:          make([]Terminal, 0, len(t)),
 		terminals:     make(termMap),
@@ -46,7 +59,8 @@ func newLexer(t ...Terminal) *lexer {
 	for _, ti := range t {
 		id := ti.Id()
 		if id == InvalidId {
-			panic(errors.Wrap(ErrDefine, "zero id"))
+			errs = append(errs, errors.Wrap(ErrDefine, "zero id"))
+			continue
 		}
 		if id < 0 {
 			prev, _ := l.internalTerms[id]
@@ -57,12 +71,13 @@ func newLexer(t ...Terminal) *lexer {
 			if prev == ti {
 				continue
 			}
-			panic(errors.Wrapf(ErrDefine, "redefine terminal %v with %v", dumpSymbol(prev), dumpSymbol(ti)))
+			errs = append(errs, errors.Wrapf(ErrDefine, "redefine terminal %v with %v", dumpSymbol(prev), dumpSymbol(ti)))
+			continue
 		}
 		l.list = append(l.list, ti)
 		l.terminals[id] = ti
 	}
-	return l
+	return l, errs
 }
 
 func (l *lexer) SymbolName(id Id) string {
@@ -110,6 +125,7 @@ func (l *lexer) Match(state *State, expected readonlyIdSet) (*State, *Match, err
 			m2 := &Match{
 				Term:  t.Id(),
 				Value: v,
+				at:    state,
 			}
 			if expected.Has(t.Id()) {
 				return nextS, m2, nil
@@ -133,6 +149,12 @@ func (l *lexer) ExpectationError(expected readonlyIdSet, pre string) error {
 		s += ": "
 	}
 	s += "expected "
+	// Error is not expected from input
+	if expected.Has(tError) {
+		only := newIdSet(expected.Ids()...)
+		only.Remove(tError)
+		expected = only
+	}
 	i, last := 0, expected.Count()-1
 	for _, t := range l.list {
 		if !expected.Has(t.Id()) {
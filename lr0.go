@@ -23,6 +23,9 @@ var (
 	//
 	//	errors.Wrap(ErrParse, "unexpected thing found")
 	ErrParse = errors.New("parse error")
+	// ErrStart is returned by Parser.ParseStart for Id which is not main
+	// non-terminal, see Parser.Starts
+	ErrStart = errors.New("not a start non-terminal")
 	// ErrState is base wrap error for parsing state
 	ErrState = errors.Wrap(ErrDefine, "bad state for table")
 	// ErrConflictReduceReduce means that there are a number of rules which
@@ -53,6 +56,21 @@ const (
 	// InvalidId id zero value for Id. It's used internally, and it's not
 	// allowed to use in definition.
 	InvalidId Id = 0
+	// EOF is a reserved pseudo-terminal Id which refers to the end of input in
+	// lookahead sets. It's not allowed to use in definition.
+	EOF = tEof
+	// Error is a reserved pseudo-terminal Id to recover from syntax errors
+	// like `error` token in yacc. It can be used in definitions:
+	//
+	//	NewNT(nStmt, "Stmt").
+	//		Is(nExpr, tSemicolon).
+	//		Is(Error, tSemicolon).Do(func(err error) Stmt { return BadStmt{err} })
+	//
+	// On syntax error parser unwinds the stack to a state which can shift
+	// Error, shifts it with the error as its value, and skips input until
+	// a token which can continue. Parse returns the result together with
+	// recovered errors then, see ParseErrors.
+	Error = tError
 )
 
 // Symbol is common interface to describe Symbol meta data
@@ -116,8 +134,9 @@ type Rule interface {
 	Subject() Id
 	// HasEOF tells whether EOF must be found in the end of input
 	//
-	// The Rule with EOF flag is Main Rule. A grammar must have exactly one Main
-	// Rule.
+	// The Rule with EOF flag is Main Rule. A grammar must have at least one
+	// Main Rule. Many Main Rules of different non-terminals are entry points
+	// sharing one table.
 	HasEOF() bool
 	// Definition of what it consists of
 	Definition() []Id
@@ -135,10 +154,59 @@ type Rule interface {
 // Parser is object preconfigured for a specific grammar, ready to parse an
 // input to evaluate the result.
 type Parser interface {
-	// Parse parses the whole input stream State.
+	// Parse parses the whole input stream State from the first main
+	// non-terminal.
 	//
-	// Returns either evaluated result or error.
+	// Returns either evaluated result or error. When the grammar recovers
+	// from syntax errors with Error, the result is returned together with
+	// recovered errors.
 	Parse(input *State) (result any, err error)
+	// ParseStart parses the whole input stream State like Parse does, but
+	// from the main non-terminal with the given Id instead of the first one.
+	// It returns ErrStart for Id which is not main non-terminal, so it can
+	// be checked once with Starts.
+	//
+	//	NewNT(nGoal, "Goal").Main().Is(nProgram),
+	//	NewNT(nGoalExpr, "GoalExpr").Main().Is(nExpr),
+	//	...
+	//	result, err := parser.ParseStart(nGoalExpr, NewState([]byte("1+2")))
+	ParseStart(start Id, input *State) (result any, err error)
+	// Starts returns Id of all main non-terminals in order of definition,
+	// which ParseStart accepts. The first one is used by Parse.
+	Starts() []Id
+	// ParseEach parses a sequence of main rules one by one from the input
+	// stream State and calls `fn` for every result as it completes. `end` is
+	// where the item ended. An item is the longest prefix of the rest of
+	// input as ParsePrefix does, and the next item starts right after it.
+	//
+	//	err := parser.ParseEach(NewState(input), func(result any, end *State) error {
+	//		fmt.Println(result, "at", end.Position())
+	//		return nil
+	//	})
+	//
+	// Error returned by `fn` stops parsing and is returned as is. An item
+	// recovered from syntax errors is passed to `fn` like others, and
+	// recovered errors of all items are returned in the end as ParseErrors.
+	ParseEach(input *State, fn func(result any, end *State) error) error
+	// ParsePrefix parses main rule from the beginning of the input stream
+	// State and returns its result with the State right after it. The
+	// result is the longest prefix of input which is the main rule, so the
+	// rest of input can be scanned by someone else, like an expression
+	// embedded in a template:
+	//
+	//	result, next, err := parser.ParsePrefix(NewState([]byte("1 + 2 }} text")))
+	//	// result is 3, next.Offset() is 5 before " }} text"
+	//
+	// When a token fails after a complete main rule, parsing falls back to
+	// where it was complete, so input like "1 + }}" gives the prefix "1" with
+	// next State before " + }}".
+	ParsePrefix(input *State) (result any, next *State, err error)
+	// Stats returns size of the parsing table
+	Stats() TableStats
+	// Grammar returns the grammar of this parser
+	Grammar() Grammar
+	// Table returns the parsing table of this parser
+	Table() Table
 }
 
 // New creates new Parser
@@ -147,6 +215,8 @@ type Parser interface {
 //
 // rules can be defined by NewNT
 //
+// options can be used to change defaults, see Option
+//
 //	parser := New(
 //		[]Terminal{
 //			NewTerm(tInt, "int").FuncByte(isDigit, bytesToInt),
@@ -167,6 +237,47 @@ type Parser interface {
 //	} else {
 //		fmt.Println("result", result)
 //	}
-func New(terminals []Terminal, rules []NonTerminalDefinition) Parser {
-	return newParser(newGrammar(terminals, rules))
+//
+// Definition mistakes are reported by panic ErrDefine. See NewE to get them
+// as error.
+func New(terminals []Terminal, rules []NonTerminalDefinition, options ...Option) Parser {
+	return newParser(newGrammar(terminals, rules), options...)
+}
+
+// NewE creates new Parser like New does, but returns definition mistakes as
+// error instead of panic. This is useful when a grammar is built at run-time.
+//
+// All found mistakes are returned at once: undefined non-terminals, unused
+// terminals, bad Do handlers, conflicts, etc. When there are several
+// mistakes, the error is DefinitionErrors. Conflicts can be found only for
+// a grammar without other mistakes. Any other panic while building the parser,
+// like ErrInternal, is returned as error too.
+//
+// Mistakes made with chainable API of NonTerminal and TerminalFactory, like
+// Do() without Is(), still panic right away, since they happen before NewE
+// is called.
+//
+//	parser, err := NewE(terminals, rules)
+//	if err != nil {
+//		var list DefinitionErrors
+//		if errors.As(err, &list) {
+//			for _, e := range list {
+//				fmt.Println(e)
+//			}
+//		}
+//		...
+//	}
+func NewE(terminals []Terminal, rules []NonTerminalDefinition, options ...Option) (p Parser, err error) {
+	// any other panic while building is returned too, since the grammar can
+	// come from outside
+	defer func() {
+		if e := recover(); e != nil {
+			p, err = nil, panicError(e)
+		}
+	}()
+	g, errs := newGrammarE(terminals, rules)
+	if len(errs) != 0 {
+		return nil, joinDefinitionErrors(errs)
+	}
+	return newParserE(g, options...)
 }
@@ -89,6 +89,51 @@ func TestParser(t *testing.T) {
 func isDigit(b byte) bool              { return b >= '0' && b <= '9' }
 func bytesToInt(b []byte) (int, error) { return strconv.Atoi(string(b)) }
 
+func TestParser_Table(t *testing.T) {
+	tbl := parser.Table()
+	if tbl.RowsCount() != parser.Stats().States || tbl.Stats() != parser.Stats() {
+		t.Fatalf("rows count %d, stats %+v", tbl.RowsCount(), tbl.Stats())
+	}
+
+	row0 := tbl.Row(0)
+	if s := fmt.Sprint(row0.Terminals()); s != fmt.Sprint([]lr0.Id{tInt, tParensOpen}) {
+		t.Errorf("row 0 terminals: %s", s)
+	}
+	if s := fmt.Sprint(row0.Gotos()); s != fmt.Sprint([]lr0.Id{nVal, nProd, nSum}) {
+		t.Errorf("row 0 gotos: %s", s)
+	}
+	if row0.AcceptEof() || row0.ReduceRule() != nil || len(row0.Lookaheads()) != 0 {
+		t.Error("row 0 can do something more")
+	}
+
+	si, ok := row0.GotoAction(nSum)
+	if !ok {
+		t.Fatal("no goto Sum")
+	}
+	if !tbl.Row(si).AcceptEof() {
+		t.Error("Sum is not accepted")
+	}
+
+	si, ok = row0.TerminalAction(tInt)
+	if !ok {
+		t.Fatal("no shift int")
+	}
+	row := tbl.Row(si)
+	if !row.IsReduceOnly() || row.ReduceRule().String() != "Val : int" {
+		t.Errorf("after int: %v", row.ReduceRule())
+	}
+
+	accepts := 0
+	for i := 0; i < tbl.RowsCount(); i++ {
+		if tbl.Row(i).AcceptEof() {
+			accepts++
+		}
+	}
+	if accepts != 1 {
+		t.Errorf("accepting rows: %d", accepts)
+	}
+}
+
 func TestInvalidId(t *testing.T) {
 	defer func() {
 		e := recover()
@@ -113,6 +158,179 @@ func TestInvalidId(t *testing.T) {
 	)
 }
 
+func TestNewE(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		p, err := lr0.NewE(
+			[]lr0.Terminal{
+				lr0.NewTerm(tInt, "int").FuncByte(isDigit, bytesToInt),
+				lr0.NewTerm(tPlus, `"+"`).Hide().Str("+"),
+			},
+			[]lr0.NonTerminalDefinition{
+				lr0.NewNT(nGoal, "Goal").Main().Is(nSum),
+				lr0.NewNT(nSum, "Sum").
+					Is(nSum, tPlus, nVal).Do(func(a, b int) int { return a + b }).
+					Is(nVal),
+				lr0.NewNT(nVal, "Val").Is(tInt),
+			},
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		v, err := p.Parse(lr0.NewState([]byte("1+2")))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v != 3 {
+			t.Fatal("result", v)
+		}
+	})
+
+	t.Run("all errors", func(t *testing.T) {
+		p, err := lr0.NewE(
+			[]lr0.Terminal{
+				lr0.NewTerm(tInt, "int").FuncByte(isDigit, bytesToInt),
+				lr0.NewTerm(tPlus, `"+"`).Hide().Str("+"),
+				lr0.NewTerm(tMinus, `"-"`).Hide().Str("-"),
+			},
+			[]lr0.NonTerminalDefinition{
+				lr0.NewNT(nGoal, "Goal").Main().Is(nSum),
+				lr0.NewNT(nSum, "Sum").
+					Is(nSum, tPlus, nVal).Do(func(a int) int { return a }).
+					Is(nProd),
+				lr0.NewNT(nVal, "Val").Is(tInt),
+			},
+		)
+		if p != nil {
+			t.Error("parser created")
+		}
+		if !errors.Is(err, lr0.ErrDefine) {
+			t.Fatal("another error:", err)
+		}
+		var list lr0.DefinitionErrors
+		if !errors.As(err, &list) {
+			t.Fatalf("not a list: %#v", err)
+		}
+		const expected = `rule for Sum: fn arguments count is 1 when wanted 2: invalid definition
+undefined non-terminals without rules:
+- #9 in NT Sum rules[1] (Sum : #9) definitions[0]
+: invalid definition
+following Terminals are not used in any Rule:
+- "-"
+: invalid definition`
+		if err.Error() != expected {
+			t.Errorf("wrong error message:\n%s", err)
+		}
+		if len(list) != 3 {
+			t.Errorf("errors count %d", len(list))
+		}
+	})
+
+	t.Run("chainable API panics", func(t *testing.T) {
+		defer func() {
+			err, ok := recover().(error)
+			if !ok || !errors.Is(err, lr0.ErrDefine) {
+				t.Fatal("another panic:", err)
+			}
+		}()
+		lr0.NewNT(nVal, "Val").Do(nil).Is(tInt)
+	})
+
+	t.Run("other panic", func(t *testing.T) {
+		p, err := lr0.NewE(
+			[]lr0.Terminal{
+				lr0.NewTerm(tInt, "int").FuncByte(isDigit, bytesToInt),
+			},
+			[]lr0.NonTerminalDefinition{
+				lr0.NewNT(nGoal, "Goal").Main().Is(nVal),
+				panicDefinition{id: nVal, name: "Val"},
+			},
+		)
+		if p != nil {
+			t.Error("parser created")
+		}
+		if !errors.Is(err, lr0.ErrInternal) || err.Error() != "oops: internal error" {
+			t.Fatal("another error:", err)
+		}
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		_, err := lr0.NewE(
+			[]lr0.Terminal{
+				lr0.NewTerm(tInt, "int").FuncByte(isDigit, bytesToInt),
+				lr0.NewTerm(tPlus, `"+"`).Hide().Str("+"),
+			},
+			[]lr0.NonTerminalDefinition{
+				lr0.NewNT(nGoal, "Goal").Main().Is(nSum),
+				lr0.NewNT(nSum, "Sum").
+					Is(nSum, tPlus, nSum).Do(func(a, b int) int { return a + b }).
+					Is(tInt),
+			},
+			lr0.WithMode(lr0.SLR1),
+		)
+		if !errors.Is(err, lr0.ErrConflictShiftReduce) {
+			t.Fatal("another error:", err)
+		}
+		var ce *lr0.ConflictError
+		if !errors.As(err, &ce) {
+			t.Fatalf("not a conflict: %#v", err)
+		}
+	})
+}
+
+// panicDefinition is a broken NonTerminalDefinition which panics
+type panicDefinition struct {
+	id   lr0.Id
+	name string
+}
+
+func (d panicDefinition) Id() lr0.Id   { return d.id }
+func (d panicDefinition) Name() string { return d.name }
+func (panicDefinition) GetRules(lr0.NamedHiddenRegistry) []lr0.Rule {
+	panic(errors.Wrap(lr0.ErrInternal, "oops"))
+}
+
+func TestHandler(t *testing.T) {
+	terminals := []lr0.Terminal{
+		lr0.NewTerm(tInt, "int").FuncByte(isDigit, bytesToInt),
+		lr0.NewTerm(tPlus, `"+"`).Hide().Str("+"),
+		lr0.NewTerm(tMinus, `"-"`).Hide().Str("-"),
+	}
+	p := lr0.New(
+		terminals,
+		[]lr0.NonTerminalDefinition{
+			lr0.NewNT(nGoal, "Goal").Main().Is(nSum),
+			lr0.NewNT(nSum, "Sum").
+				Is(nSum, tPlus, nVal).Do(lr0.Do2(func(a, b int) int { return a + b })).
+				Is(nSum, tMinus, nVal).Do(lr0.Do2(func(a, b int) int { return a - b })).
+				Is(nVal),
+			lr0.NewNT(nVal, "Val").Is(tInt),
+		},
+	)
+	v, err := p.Parse(lr0.NewState([]byte("1+2-4")))
+	if err != nil || v != -1 {
+		t.Fatalf("result %#v, %v", v, err)
+	}
+
+	_, err = lr0.NewE(
+		terminals,
+		[]lr0.NonTerminalDefinition{
+			lr0.NewNT(nGoal, "Goal").Main().Is(nSum),
+			lr0.NewNT(nSum, "Sum").
+				Is(nSum, tPlus, nVal).Do(lr0.Do2(func(a, b int) int { return a + b })).
+				Is(nSum, tMinus, nVal).Do(lr0.Do2(func(a int, b string) string { return b })).
+				Is(nVal),
+			lr0.NewNT(nVal, "Val").Is(tInt),
+		},
+	)
+	if !errors.Is(err, lr0.ErrDefine) {
+		t.Fatal("another error:", err)
+	}
+	const expected = `rule for Sum: Sum : Sum "-" Val: argument 2 of type string cannot be assigned with Val of type int: invalid definition`
+	if err.Error() != expected {
+		t.Errorf("wrong error message:\n%s", err)
+	}
+}
+
 func TestCommentExample1(t *testing.T) {
 	p := lr0.New(
 		[]lr0.Terminal{
@@ -6,4 +6,6 @@ type Match struct {
 	Term Id
 	// What value it returned
 	Value any
+	// at is where the Terminal starts after whitespaces
+	at *State
 }
@@ -1,6 +1,8 @@
 package lr0
 
 import (
+	"reflect"
+
 	"github.com/pkg/errors"
 )
 
@@ -29,6 +31,7 @@ type NonTerminal struct {
 	id          Id
 	name        string
 	main        bool
+	typ         reflect.Type
 	definitions []nonTerminalDefinition
 }
 
@@ -38,10 +41,12 @@ func (n *NonTerminal) Name() string { return n.name }
 
 // Main marks this non-terminal as main
 //
-// Main non-terminal must have exactly one definition. Exactly one main rule
-// must be defined in grammar.
+// Main non-terminal must have exactly one definition. At least one main rule
+// must be defined in grammar. Many main non-terminals are different entry
+// points to parse with the same table, see Parser.ParseStart.
 //
 //	NewNT(nGoal).Main().Is(nSum)
+//	NewNT(nGoalExpr).Main().Is(nExpr)
 func (n *NonTerminal) Main() *NonTerminal {
 	if l := len(n.definitions); l > 1 {
 		panic(errors.Wrapf(ErrDefine, "main non-terminal must have the only definition, here are %d", l))
@@ -50,9 +55,26 @@ func (n *NonTerminal) Main() *NonTerminal {
 	return n
 }
 
+// Type declares type of values of this non-terminal. New checks that every
+// rule gives a value of this type, and that Do handlers using this
+// non-terminal take it.
+//
+// Without Type the type is inferred from rules when all of them give the
+// same type.
+//
+//	NewNT(nSum, "Sum").Type(reflect.TypeOf(0))
+func (n *NonTerminal) Type(typ reflect.Type) *NonTerminal {
+	n.typ = typ
+	return n
+}
+
+func (n *NonTerminal) valueType() reflect.Type { return n.typ }
+
 // Is adds one more alternative definition for the non-terminal
 //
 // Is can be followed by Do() to define evaluation for this definition.
+//
+// Use IsEmpty() to add empty definition.
 func (n *NonTerminal) Is(id Id, ids ...Id) *NonTerminal {
 	if n.main && len(n.definitions) > 0 {
 		panic(errors.Wrap(ErrDefine, "main non-terminal must have the only definition"))
@@ -63,6 +85,23 @@ func (n *NonTerminal) Is(id Id, ids ...Id) *NonTerminal {
 	return n
 }
 
+// IsEmpty adds one more alternative empty definition for the non-terminal, so
+// the non-terminal becomes optional where it's used.
+//
+// IsEmpty can be followed by Do() with a func without arguments to define
+// the value. Without Do() the value is nil.
+//
+//	NewNT(nSign, "Sign").
+//		Is(tMinus).Do(func() int { return -1 }).
+//		IsEmpty().Do(func() int { return 1 })
+func (n *NonTerminal) IsEmpty() *NonTerminal {
+	if n.main && len(n.definitions) > 0 {
+		panic(errors.Wrap(ErrDefine, "main non-terminal must have the only definition"))
+	}
+	n.definitions = append(n.definitions, nonTerminalDefinition{})
+	return n
+}
+
 // Do sets a func how to evaluate return value of this non-terminal for the
 // latest `Is()` case.
 //
@@ -72,7 +111,8 @@ func (n *NonTerminal) Is(id Id, ids ...Id) *NonTerminal {
 //	[]any{ /* one value here */ }
 //
 // then `Do()` subsequent can be omitted: then the value of children
-// node will be returned from this rule:
+// node will be returned from this rule. Similarly, when a definition evaluates
+// no values, the omitted `Do()` gives nil value:
 //
 //	NewNT(nSum, "Sum").
 //		Is(nSum, tPlus, nVal).Do(func (a, b int) int { return a+b }).
@@ -82,6 +122,13 @@ func (n *NonTerminal) Is(id Id, ids ...Id) *NonTerminal {
 //		Is(nVal)
 //		// -----^^^^ here is no `Do(func (v int) int { return v })`
 //		//           `Do(nil)` will do the same in this case
+//
+// A func given as is will be called with reflection. Use Handler from Do0,
+// Do1, etc. to call it directly with types checked by compiler:
+//
+//	Is(nSum, tPlus, nVal).Do(Do2(func (a, b int) int { return a+b }))
+//
+// A func can take Span as the first argument before values, see Span.
 func (n *NonTerminal) Do(calcHandler any) *NonTerminal {
 	l := len(n.definitions)
 	if l == 0 {
@@ -95,20 +142,67 @@ func (n *NonTerminal) Do(calcHandler any) *NonTerminal {
 	return n
 }
 
+// Prec sets precedence for the latest `Is()` case to be the same as the
+// given Id has, like `%prec` in yacc. The Id must be declared by Left, Right
+// or NonAssoc, but it's not necessary to be defined Terminal.
+//
+// By default, a rule has precedence of its last terminal.
+//
+//	NewNT(nExpr, "Expr").
+//		Is(nExpr, tMinus, nExpr).Do(func (a, b int) int { return a-b }).
+//		Is(tMinus, nExpr).Prec(tUnaryMinus).Do(func (a int) int { return -a })
+//	...
+//	New(terminals, rules,
+//		Left(tMinus),
+//		Right(tUnaryMinus),
+//	)
+func (n *NonTerminal) Prec(id Id) *NonTerminal {
+	l := len(n.definitions)
+	if l == 0 {
+		panic(errors.Wrap(ErrDefine, "using Prec() without Is()"))
+	}
+	to := &n.definitions[l-1]
+	if to.prec != InvalidId {
+		panic(errors.Wrap(ErrDefine, "using Prec() again without Is()"))
+	}
+	to.prec = id
+	return n
+}
+
 // GetRules return actual rules built for this non-terminal
+//
+// All definition mistakes are reported by panic ErrDefine. When there are
+// several mistakes, the panic value is DefinitionErrors.
 func (n *NonTerminal) GetRules(l NamedHiddenRegistry) []Rule {
+	res, errs := n.getRules(l)
+	if err := joinDefinitionErrors(errs); err != nil {
+		panic(err)
+	}
+	return res
+}
+
+// getRules returns rules like GetRules does and all definition errors found
+//
+// Rules are returned even with errors to let check the rest of grammar.
+func (n *NonTerminal) getRules(l NamedHiddenRegistry) ([]Rule, []error) {
+	var errs []error
 	c := len(n.definitions)
 	if c == 0 {
-		panic(errors.Wrap(ErrDefine, "no definitions by Is()"))
+		errs = append(errs, errors.Wrapf(ErrDefine, "non-terminal %s: no definitions by Is()", dumpSymbol(n)))
 	}
 	res := make([]Rule, 0, c)
 	for _, def := range n.definitions {
-		res = append(res, newRule(n, n.main, def, l))
+		r, err := newRuleE(n, n.main, def, l)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		res = append(res, r)
 	}
-	return res
+	return res, errs
 }
 
 type nonTerminalDefinition struct {
 	items       []Id
 	calcHandler any
+	prec        Id
 }
@@ -0,0 +1,109 @@
+package lr0
+
+import (
+	"fmt"
+)
+
+// TableMode selects an algorithm to build parsing table
+type TableMode int
+
+const (
+	// LR0 builds LR(0) table. A state which can reduce will do it on any
+	// input which cannot be shifted. This is default mode.
+	LR0 TableMode = iota
+	// SLR1 builds SLR(1) table. Reduce actions are bound to lookahead
+	// terminals from FOLLOW set of a rule subject, so a conflict is reported
+	// only when FOLLOW and shift sets actually overlap.
+	SLR1
+	// LALR1 builds LALR(1) table. It has the same states as LR(0) table, but
+	// lookahead terminals are calculated for every state separately, so it
+	// handles more grammars than SLR1 does.
+	LALR1
+	// LR1 builds canonical LR(1) table. Every item has its own lookahead set,
+	// so states having same LR(0) core but different lookaheads are not
+	// merged. It handles more grammars than LALR1 does, but the table can be
+	// much bigger. See TableStats to compare.
+	LR1
+)
+
+// String returns name of the mode like "LALR(1)", so test names and messages
+// are readable
+func (m TableMode) String() string {
+	switch m {
+	case LR0:
+		return "LR(0)"
+	case SLR1:
+		return "SLR(1)"
+	case LALR1:
+		return "LALR(1)"
+	case LR1:
+		return "LR(1)"
+	default:
+		return fmt.Sprintf("TableMode(%d)", int(m))
+	}
+}
+
+// TableStats describes size of a built table
+type TableStats struct {
+	// Mode which was used to build the table
+	Mode TableMode
+	// States is count of states in the table
+	States int
+	// Cores is count of different LR(0) cores of states. This is count of
+	// states LR(0), SLR(1) or LALR(1) table would have for the same grammar.
+	// It's equal to States in all modes except LR1.
+	Cores int
+}
+
+// Option configures Parser creation in New
+type Option func(*config)
+
+// WithMode sets algorithm to build parsing table
+//
+//	New(terminals, rules, WithMode(LALR1))
+func WithMode(mode TableMode) Option {
+	return func(c *config) {
+		c.mode = mode
+	}
+}
+
+// WithTree makes Parse to return *Node tree instead of evaluation by Do
+// handlers
+//
+//	p := New(terminals, rules, WithTree())
+//	result, err := p.Parse(NewState(input))
+//	...
+//	root := result.(*Node)
+//
+// The result is the Node of the only symbol in the main rule definition.
+func WithTree() Option {
+	return func(c *config) {
+		c.tree = true
+	}
+}
+
+// WithHiddenNodes makes tree to have Node for hidden Terminals too. It
+// implies WithTree.
+func WithHiddenNodes() Option {
+	return func(c *config) {
+		c.tree = true
+		c.treeHidden = true
+	}
+}
+
+type config struct {
+	tree       bool
+	treeHidden bool
+	mode       TableMode
+	precedence map[Id]precedence
+	precLevels int
+	table      *TableData
+}
+
+func newConfig(opts []Option) *config {
+	c := &config{}
+	for _, o := range opts {
+		o(c)
+	}
+	return c
+}
@@ -1,78 +1,298 @@
 package lr0
 
 import (
+	"fmt"
 	"io"
 
 	"github.com/pkg/errors"
 )
 
-func newParser(g *grammar) Parser {
+func newParser(g *grammar, opts ...Option) Parser {
 	return &parser{
 		g: g,
-		t: newTable(g),
+		t: newTable(g, opts...),
+		c: newConfig(opts),
 	}
 }
 
+func newParserE(g *grammar, opts ...Option) (Parser, error) {
+	t, errs := newTableE(g, opts...)
+	if len(errs) != 0 {
+		return nil, joinDefinitionErrors(errs)
+	}
+	return &parser{g: g, t: t, c: newConfig(opts)}, nil
+}
+
 type parser struct {
 	g *grammar
 	t *table
+	c *config
 }
 
+func (p *parser) Stats() TableStats { return p.t.stats }
+func (p *parser) Grammar() Grammar  { return p.g }
+func (p *parser) Table() Table      { return p.t }
+
 func (p *parser) Parse(input *State) (result any, err error) {
-	st := newStack(p.t)
+	result, _, err = p.parse(input, 0, false)
+	// read error looks like EOF while parsing
+	if rErr := input.readErr(); rErr != nil {
+		return nil, errors.Wrap(rErr, "read input")
+	}
+	return
+}
+
+func (p *parser) ParseEach(input *State, fn func(result any, end *State) error) error {
+	// errs are recovered errors of all items
+	var errs ParseErrors
+	for next := input; !p.g.skipWhitespaces(next).IsEOF(); {
+		result, end, err := p.parse(next, 0, true)
+		if list, ok := err.(ParseErrors); ok && end != nil {
+			// the item is recovered, so parsing goes on
+			errs = append(errs, list...)
+			err = nil
+		}
+		if err == nil && end.Offset() == next.Offset() {
+			// nothing parsed, so it will repeat endlessly
+			err = WithSource(NewParseError("unexpected input"), end)
+		}
+		if rErr := input.readErr(); rErr != nil {
+			return errors.Wrap(rErr, "read input")
+		}
+		if err != nil {
+			if len(errs) == 0 {
+				return err
+			}
+			if list, ok := err.(ParseErrors); ok {
+				return append(errs, list...)
+			}
+			return append(errs, err)
+		}
+		if err = fn(result, end); err != nil {
+			return err
+		}
+		next = end
+	}
+	if rErr := input.readErr(); rErr != nil {
+		return errors.Wrap(rErr, "read input")
+	}
+	if len(errs) != 0 {
+		return errs
+	}
+	return nil
+}
+
+func (p *parser) ParsePrefix(input *State) (result any, next *State, err error) {
+	result, next, err = p.parse(input, 0, true)
+	if rErr := input.readErr(); rErr != nil {
+		return nil, nil, errors.Wrap(rErr, "read input")
+	}
+	return
+}
+
+func (p *parser) ParseStart(start Id, input *State) (result any, err error) {
+	// initial states go in order of main rules
+	for si, ri := range p.g.mainIndices {
+		if p.g.rules[ri].Subject() != start {
+			continue
+		}
+		result, _, err = p.parse(input, si, false)
+		if rErr := input.readErr(); rErr != nil {
+			return nil, errors.Wrap(rErr, "read input")
+		}
+		return
+	}
+	return nil, errors.Wrapf(ErrStart, "%s", dumpId(start, p.g))
+}
+
+func (p *parser) Starts() []Id {
+	ids := make([]Id, 0, len(p.g.mainIndices))
+	for _, ri := range p.g.mainIndices {
+		ids = append(ids, p.g.rules[ri].Subject())
+	}
+	return ids
+}
+
+// parse parses main rule from the input starting with the given initial
+// state. With `prefix` flag it returns the longest prefix of the input which
+// is the main rule, and the State where it stopped. So when a token fails
+// later, parsing falls back to the last place where the input could end.
+//
+// When the grammar uses Error, syntax errors are recovered, and the result is
+// returned together with recovered errors.
+func (p *parser) parse(input *State, start tableStateIndex, prefix bool) (result any, end *State, err error) {
+	st := newStack(p.t, start)
+	st.tree, st.treeHidden = p.c.tree, p.c.treeHidden
+	st.origin = input
+
+	var rec *recovery
+	if p.g.IsTerminal(tError) {
+		rec = &recovery{}
+	}
 
 	var (
 		next = input
+		m    *Match
 		ok   bool
 		to   tableStateIndex
+		// tail is the error for unknown input after the prefix
+		tail error
+		// last is the last place where the prefix could end
+		last *prefixEnd
 	)
 Goal:
 	for {
 		at := next
+		if prefix && st.acceptsEof() {
+			st.mark()
+			last = &prefixEnd{at: at}
+		}
+		// stream can discard data before the current token, but not before
+		// the prefix end to fall back to
+		if last != nil {
+			last.at.release()
+		} else {
+			at.release()
+		}
 		for st.Current().IsReduceOnly() {
 			ok, err = st.Reduce()
 			if err != nil {
-				return nil, WithSource(err, at)
+				return nil, nil, rec.fatal(WithSource(err, at))
 			}
 			if !ok {
 				// if this happens ever?
 				// REFACT: looks like this will never happen now
 				// no reduce rule - unexpected input
 				//st.Current().TerminalsSet()
-				return nil, WithSource(NewParseError("unexpected input 1"), at)
+				return nil, nil, rec.fatal(WithSource(NewParseError("unexpected input 1"), at))
 			}
 		}
 
-		var m *Match
+		m = nil
 		if !next.IsEOF() {
 			next, m, err = p.g.Match(next, st.Current().TerminalsSet())
 			if err != nil && err != io.EOF {
-				return nil, errors.Wrap(err, "unexpected input")
+				err = errors.Wrap(err, "unexpected input")
+				if prefix {
+					// the rest is not known here, so it's like EOF if the
+					// main rule is done
+					tail = err
+					next, m = at, nil
+				} else {
+					// unknown input is skipped by runes while recovering
+					token := p.g.skipWhitespaces(at)
+					retry, fatal := rec.recover(st, err, token, false)
+					if fatal != nil {
+						return nil, nil, fatal
+					}
+					next = at
+					if !retry {
+						next, _ = token.TakeRune()
+					}
+					continue Goal
+				}
+			}
+		}
+
+		// fallback returns to the last place where the prefix could end, so
+		// the rest is handled like EOF there
+		fallback := func() bool {
+			if !prefix || last == nil {
+				return false
+			}
+			st.restore()
+			at, next, m = last.at, last.at, nil
+			last = nil
+			return true
+		}
+
+		// syntaxError handles the error at the current token. It returns
+		// true when the token must be tried again after recovery, or false
+		// when it must be skipped.
+		syntaxError := func(err error) (retry bool, fatal error) {
+			token := p.g.skipWhitespaces(at)
+			if m != nil {
+				token = m.at
 			}
+			return rec.recover(st, WithSource(err, at), token, m == nil)
 		}
 
 		for {
+			lookahead := tEof
 			if m != nil {
 				if to, ok = st.Current().TerminalAction(m.Term); ok {
-					st.Shift(to, m.Term, m.Value)
+					st.ShiftAt(to, m.Term, m.Value, m.at, next)
+					rec.shifted()
 					break
 				}
+				if st.Current().IsDenied(m.Term) {
+					if fallback() {
+						continue
+					}
+					retry, fatal := syntaxError(NewParseError(fmt.Sprintf("unexpected input: %s is non-associative", dumpId(m.Term, p.g))))
+					if fatal != nil {
+						return nil, nil, fatal
+					}
+					if !retry {
+						continue Goal
+					}
+					continue
+				}
+				lookahead = m.Term
+			}
+			ok, err = st.ReduceFor(lookahead)
+			if err != nil {
+				return nil, nil, rec.fatal(WithSource(err, at))
+			}
+			if ok {
+				continue
 			}
 			if st.Current().AcceptEof() {
 				if m == nil {
+					end = next
 					break Goal
 				}
-				return nil, WithSource(NewParseError("unexpected input instead of EOF"), at)
+				if prefix {
+					end = at
+					break Goal
+				}
+				retry, fatal := syntaxError(NewParseError("unexpected input instead of EOF"))
+				if fatal != nil {
+					return nil, nil, fatal
+				}
+				if !retry {
+					continue Goal
+				}
+				continue
 			}
 
 			ok, err = st.Reduce()
 			if err != nil {
-				return nil, WithSource(err, at)
+				return nil, nil, rec.fatal(WithSource(err, at))
 			}
 			if !ok {
-				return nil, WithSource(p.g.ExpectationError(st.Current().TerminalsSet(), "unexpected input"), at)
+				if fallback() {
+					continue
+				}
+				if tail != nil {
+					return nil, nil, rec.fatal(tail)
+				}
+				retry, fatal := syntaxError(p.g.ExpectationError(st.Current().TerminalsSet(), "unexpected input"))
+				if fatal != nil {
+					return nil, nil, fatal
+				}
+				if !retry {
+					continue Goal
+				}
 			}
 		}
 	}
-	return st.Done(), nil
+	return st.Done(), end, rec.result()
+}
+
+// prefixEnd is a place where the prefix could end. Stack items at the place
+// are kept by stack mark.
+type prefixEnd struct {
+	// at is the State before the next token
+	at *State
 }
@@ -1,6 +1,8 @@
 package lr0
 
 import (
+	"fmt"
+	"reflect"
 	"strings"
 	"testing"
 	"unicode"
@@ -70,7 +72,7 @@ func TestParser(t *testing.T) {
 		if !errors.Is(err, ErrParse) {
 			t.Fatal("wrong error type:", err)
 		}
-		if err.Error() != "unexpected input: expected int: parse error near ⟪42/3*⟫⏵⟪?0⟫" {
+		if err.Error() != "unexpected input: expected int: parse error at 1:6 near ⟪42/3*⟫⏵⟪?0⟫" {
 			t.Fatal("wrong error message:", err)
 		}
 	})
@@ -79,7 +81,7 @@ func TestParser(t *testing.T) {
 		if !errors.Is(err, ErrParse) {
 			t.Fatal("wrong error type:", err)
 		}
-		if err.Error() != "unexpected input: expected int: parse error near ⟪42/3*⟫⏵⟪*7⟫" {
+		if err.Error() != "unexpected input: expected int: parse error at 1:6 near ⟪42/3*⟫⏵⟪*7⟫" {
 			t.Fatal("wrong error message:", err)
 		}
 	})
@@ -88,8 +90,396 @@ func TestParser(t *testing.T) {
 		if !errors.Is(err, ErrParse) {
 			t.Fatal("wrong error type:", err)
 		}
-		if !strings.Contains(err.Error(), "unexpected input instead of EOF: parse error near ⟪42/3⟫⏵⟪␠7⟫") {
+		if !strings.Contains(err.Error(), "unexpected input instead of EOF: parse error at 1:5 near ⟪42/3⟫⏵⟪␠7⟫") {
 			t.Fatal("wrong error message:", err)
 		}
 	})
 }
+
+func TestParser_SLR1(t *testing.T) {
+	// Goal : S $
+	// S    : Val "=" | Sum "+" int
+	// Val  : int
+	// Sum  : int
+	p := newParser(newGrammar(
+		[]Terminal{
+			NewTerm(tInt, "int").FuncByte(isDigit, bytesToInt),
+			NewTerm(tPlus, `"+"`).Hide().Str("+"),
+			NewTerm(tMinus, `"="`).Hide().Str("="),
+		},
+		[]NonTerminalDefinition{
+			NewNT(nGoal, "Goal").Main().Is(nProd),
+			NewNT(nProd, "S").
+				Is(nVal, tMinus).Do(func(v int) int { return -v }).
+				Is(nSum, tPlus, tInt).Do(calc2IntSum),
+			NewNT(nVal, "Val").Is(tInt),
+			NewNT(nSum, "Sum").Is(tInt),
+		},
+	), WithMode(SLR1))
+
+	for _, c := range []struct {
+		input  string
+		result int
+		err    string
+	}{
+		{input: "42=", result: -42},
+		{input: "42+37", result: 79},
+		{input: "42", err: `unexpected input: expected "+" or "=": parse error at 1:3 near ⟪42⟫⏵<EOF>`},
+		{input: "42+", err: `unexpected input: expected int: parse error at 1:4 near ⟪42+⟫⏵<EOF>`},
+		{input: "42=7", err: `unexpected input instead of EOF: parse error at 1:4 near ⟪42=⟫⏵⟪7⟫`},
+	} {
+		t.Run(c.input, func(t *testing.T) {
+			v, err := p.Parse(NewState([]byte(c.input)))
+			if c.err != "" {
+				if err == nil || err.Error() != c.err {
+					t.Fatal("wrong error:", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal("parse failed:", err)
+			}
+			if v != c.result {
+				t.Fatalf("result is %#v", v)
+			}
+		})
+	}
+}
+
+func TestParser_LALR1(t *testing.T) {
+	// Goal : S $
+	// S    : L "=" R | R
+	// L    : "*" R | id
+	// R    : L
+	p := newParser(newGrammar(
+		[]Terminal{
+			NewTerm(tIdent, "id").Func(matchIdentifier),
+			NewTerm(tMinus, `"="`).Hide().Str("="),
+			NewTerm(tMul, `"*"`).Hide().Str("*"),
+			NewWhitespace().FuncRune(unicode.IsSpace),
+		},
+		[]NonTerminalDefinition{
+			NewNT(nGoal, "Goal").Main().Is(nSum),
+			NewNT(nSum, "S").
+				Is(nProd, tMinus, nVal).Do(func(l, r string) string { return l + " := " + r }).
+				Is(nVal),
+			NewNT(nProd, "L").
+				Is(tMul, nVal).Do(func(r string) string { return "*(" + r + ")" }).
+				Is(tIdent),
+			NewNT(nVal, "R").Is(nProd),
+		},
+	), WithMode(LALR1))
+
+	for input, result := range map[string]string{
+		"a":        "a",
+		"**a":      "*(*(a))",
+		"*a = b":   "*(a) := b",
+		"a = **b":  "a := *(*(b))",
+		"*b = * a": "*(b) := *(a)",
+	} {
+		t.Run(input, func(t *testing.T) {
+			v, err := p.Parse(NewState([]byte(input)))
+			if err != nil {
+				t.Fatal("parse failed:", err)
+			}
+			if v != result {
+				t.Fatalf("result is %#v", v)
+			}
+		})
+	}
+
+	_, err := p.Parse(NewState([]byte("a = b = c")))
+	if err == nil || err.Error() != "unexpected input instead of EOF: parse error at 1:6 near ⟪a␠=␠b⟫⏵⟪␠=␠c⟫" {
+		t.Fatal("wrong error:", err)
+	}
+}
+
+func TestParser_LR1(t *testing.T) {
+	// Goal : S $
+	// S    : "(" A ")" | "[" B ")" | "(" B "]" | "[" A "]"
+	// A    : int
+	// B    : int
+	p := newParser(newGrammar(
+		[]Terminal{
+			NewTerm(tInt, "int").FuncByte(isDigit, bytesToInt),
+			NewTerm(tPlus, `"("`).Hide().Str("("),
+			NewTerm(tMinus, `")"`).Hide().Str(")"),
+			NewTerm(tMul, `"["`).Hide().Str("["),
+			NewTerm(tDiv, `"]"`).Hide().Str("]"),
+		},
+		[]NonTerminalDefinition{
+			NewNT(nGoal, "Goal").Main().Is(nSum),
+			NewNT(nSum, "S").
+				Is(tPlus, nProd, tMinus).
+				Is(tMul, nVal, tMinus).
+				Is(tPlus, nVal, tDiv).
+				Is(tMul, nProd, tDiv),
+			NewNT(nProd, "A").Is(tInt).Do(func(v int) int { return v * 10 }),
+			NewNT(nVal, "B").Is(tInt).Do(func(v int) int { return v * 100 }),
+		},
+	), WithMode(LR1))
+
+	for input, result := range map[string]int{
+		"(1)": 10,
+		"[2)": 200,
+		"(3]": 300,
+		"[4]": 40,
+	} {
+		t.Run(input, func(t *testing.T) {
+			v, err := p.Parse(NewState([]byte(input)))
+			if err != nil {
+				t.Fatal("parse failed:", err)
+			}
+			if v != result {
+				t.Fatalf("result is %#v", v)
+			}
+		})
+	}
+
+	if s := p.Stats(); s.States <= s.Cores {
+		t.Errorf("stats: %+v", s)
+	}
+}
+
+func TestParser_Empty(t *testing.T) {
+	// Goal : List $
+	// List : List Item | ε
+	// Item : Sign int
+	// Sign : "-" | ε
+	g := newGrammar(
+		[]Terminal{
+			NewTerm(tInt, "int").FuncByte(isDigit, bytesToInt),
+			NewTerm(tMinus, `"-"`).Hide().Str("-"),
+			NewWhitespace().FuncRune(unicode.IsSpace),
+		},
+		[]NonTerminalDefinition{
+			NewNT(nGoal, "Goal").Main().Is(nSum),
+			NewNT(nSum, "List").
+				Is(nSum, nProd).Do(func(l []int, v int) []int { return append(l, v) }).
+				IsEmpty().Do(func() []int { return []int{} }),
+			NewNT(nProd, "Item").Is(nVal, tInt).Do(func(s, v int) int { return s * v }),
+			NewNT(nVal, "Sign").
+				Is(tMinus).Do(func() int { return -1 }).
+				IsEmpty().Do(func() int { return 1 }),
+		},
+	)
+
+	for _, mode := range []TableMode{SLR1, LALR1, LR1} {
+		p := newParser(g, WithMode(mode))
+		for input, result := range map[string]string{
+			"":           "[]",
+			"  ":         "[]",
+			"42":         "[42]",
+			"-42":        "[-42]",
+			"1 -2 3 - 4": "[1 -2 3 -4]",
+		} {
+			t.Run(fmt.Sprintf("%v: %q", mode, input), func(t *testing.T) {
+				v, err := p.Parse(NewState([]byte(input)))
+				if err != nil {
+					t.Fatal("parse failed:", err)
+				}
+				if s := fmt.Sprint(v); s != result {
+					t.Fatalf("result is %#v", v)
+				}
+			})
+		}
+
+		_, err := p.Parse(NewState([]byte("1 --2")))
+		if err == nil || err.Error() != "unexpected input: expected int: parse error at 1:4 near ⟪1␠-⟫⏵⟪-2⟫" {
+			t.Errorf("%v: wrong error: %v", mode, err)
+		}
+	}
+
+	t.Run("nil value", func(t *testing.T) {
+		p := newParser(newGrammar(
+			[]Terminal{
+				NewTerm(tInt, "int").FuncByte(isDigit, bytesToInt),
+			},
+			[]NonTerminalDefinition{
+				NewNT(nGoal, "Goal").Main().Is(nSum),
+				NewNT(nSum, "Opt").Is(tInt).IsEmpty(),
+			},
+		), WithMode(SLR1))
+		v, err := p.Parse(NewState(nil))
+		if err != nil || v != nil {
+			t.Fatalf("result is %#v, %v", v, err)
+		}
+		v, err = p.Parse(NewState([]byte("42")))
+		if err != nil || v != 42 {
+			t.Fatalf("result is %#v, %v", v, err)
+		}
+	})
+}
+
+func TestParser_ParseEach(t *testing.T) {
+	for _, mode := range []TableMode{LR0, SLR1, LALR1, LR1} {
+		p := New(
+			[]Terminal{
+				NewTerm(tInt, "int").FuncByte(isDigit, bytesToInt),
+				NewTerm(tPlus, `"+"`).Hide().Str("+"),
+				NewWhitespace().FuncRune(unicode.IsSpace),
+			},
+			[]NonTerminalDefinition{
+				NewNT(nGoal, "Goal").Main().Is(nSum),
+				NewNT(nSum, "Sum").
+					Is(nSum, tPlus, tInt).Do(calc2IntSum).
+					Is(tInt),
+			},
+			WithMode(mode),
+		)
+
+		var results []string
+		err := p.ParseEach(NewState([]byte(" 1+2 3\n4 + 5+6 ")), func(result any, end *State) error {
+			results = append(results, fmt.Sprintf("%v@%d", result, end.Offset()))
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("%v: %v", mode, err)
+		}
+		if s := strings.Join(results, " "); s != "3@4 3@6 15@15" {
+			t.Errorf("%v: results %s", mode, s)
+		}
+
+		err = p.ParseEach(NewState([]byte("1 2+ +")), func(any, *State) error { return nil })
+		if err == nil || err.Error() != "unexpected input: expected int: parse error at 1:4 near ⟪1␠2⟫⏵⟪+␠+⟫" {
+			t.Errorf("%v: error %v", mode, err)
+		}
+		stop := errors.New("stop")
+		calls := 0
+		err = p.ParseEach(NewState([]byte("1 2 3")), func(any, *State) error {
+			calls++
+			return stop
+		})
+		if err != stop || calls != 1 {
+			t.Errorf("%v: stop error %v after %d calls", mode, err, calls)
+		}
+	}
+}
+
+func TestParser_ParsePrefix(t *testing.T) {
+	type testCase struct {
+		input  string
+		result int
+		next   int
+		err    string
+	}
+	for _, mode := range []TableMode{LR0, SLR1, LALR1, LR1} {
+		p := New(
+			[]Terminal{
+				NewTerm(tInt, "int").FuncByte(isDigit, bytesToInt),
+				NewTerm(tPlus, `"+"`).Hide().Str("+"),
+				NewWhitespace().FuncRune(unicode.IsSpace),
+			},
+			[]NonTerminalDefinition{
+				NewNT(nGoal, "Goal").Main().Is(nSum),
+				NewNT(nSum, "Sum").
+					Is(nSum, tPlus, tInt).Do(calc2IntSum).
+					Is(tInt),
+			},
+			WithMode(mode),
+		)
+		for _, c := range []testCase{
+			{input: "1+2", result: 3, next: 3},
+			{input: "1 + 2 }} text", result: 3, next: 5},
+			{input: "1+2 - 3", result: 3, next: 3},
+			{input: "42 3", result: 42, next: 2},
+			{input: "1 + }}", result: 1, next: 1},
+			{input: "1+}", result: 1, next: 1},
+			{input: "1+2+ 3 +", result: 6, next: 6},
+			{input: "1+2 + +3", result: 3, next: 3},
+			{input: "}}", err: "unexpected input: expected int: parse error at 1:1 near ⏵⟪}}⟫"},
+		} {
+			t.Run(fmt.Sprintf("%v: %s", mode, c.input), func(t *testing.T) {
+				v, next, err := p.ParsePrefix(NewState([]byte(c.input)))
+				if c.err != "" {
+					if err == nil || err.Error() != c.err {
+						t.Fatalf("wrong error: %v", err)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				if v != c.result || next.Offset() != c.next {
+					t.Errorf("result %#v, next at %d", v, next.Offset())
+				}
+			})
+		}
+	}
+
+	t.Run("reduce to fall back", func(t *testing.T) {
+		for _, mode := range []TableMode{LR0, SLR1, LALR1, LR1} {
+			p := New(
+				[]Terminal{
+					NewTerm(tInt, "int").FuncByte(isDigit, bytesToInt),
+					NewTerm(tPlus, `"+"`).Hide().Str("+"),
+					NewTerm(tMul, `"*"`).Hide().Str("*"),
+				},
+				[]NonTerminalDefinition{
+					NewNT(nGoal, "Goal").Main().Is(nSum),
+					NewNT(nSum, "Sum").
+						Is(nSum, tPlus, nProd).Do(calc2IntSum).
+						Is(nProd),
+					NewNT(nProd, "Prod").
+						Is(nProd, tMul, tInt).Do(func(a, b int) int { return a * b }).
+						Is(tInt),
+				},
+				WithMode(mode),
+			)
+			// Prod must be reduced to Sum at the prefix end
+			v, next, err := p.ParsePrefix(NewState([]byte("1+2*3*}")))
+			if err != nil {
+				t.Fatalf("%v: %v", mode, err)
+			}
+			if v != 7 || next.Offset() != 5 {
+				t.Errorf("%v: result %#v, next at %d", mode, v, next.Offset())
+			}
+		}
+	})
+}
+
+func TestParser_ParseStart(t *testing.T) {
+	const nGoalVal = nGoal + 1
+	terminals := []Terminal{
+		NewTerm(tInt, "int").FuncByte(isDigit, bytesToInt),
+		NewTerm(tPlus, `"+"`).Hide().Str("+"),
+		NewTerm(tMul, `"*"`).Hide().Str("*"),
+	}
+	rules := []NonTerminalDefinition{
+		NewNT(nGoal, "Goal").Main().Is(nSum),
+		NewNT(nGoalVal, "GoalVal").Main().Is(nProd),
+		NewNT(nSum, "Sum").
+			Is(nSum, tPlus, nProd).Do(calc2IntSum).
+			Is(nProd),
+		NewNT(nProd, "Prod").
+			Is(nProd, tMul, nVal).Do(func(a, b int) int { return a * b }).
+			Is(nVal),
+		NewNT(nVal, "Val").Is(tInt),
+	}
+	for _, mode := range []TableMode{LR0, SLR1, LALR1, LR1} {
+		p := New(terminals, rules, WithMode(mode))
+		loaded := New(terminals, rules, WithMode(mode), WithTable(p.Table().Data()))
+		for _, p := range []Parser{p, loaded} {
+			if ids := p.Starts(); !reflect.DeepEqual(ids, []Id{nGoal, nGoalVal}) {
+				t.Errorf("%v: starts %v", mode, ids)
+			}
+			if v, err := p.Parse(NewState([]byte("2+3*4"))); err != nil || v != 14 {
+				t.Errorf("%v: Parse: %#v, %v", mode, v, err)
+			}
+			if v, err := p.ParseStart(nGoal, NewState([]byte("2+3*4"))); err != nil || v != 14 {
+				t.Errorf("%v: ParseStart Goal: %#v, %v", mode, v, err)
+			}
+			if v, err := p.ParseStart(nGoalVal, NewState([]byte("3*4"))); err != nil || v != 12 {
+				t.Errorf("%v: ParseStart GoalVal: %#v, %v", mode, v, err)
+			}
+			_, err := p.ParseStart(nGoalVal, NewState([]byte("2+3")))
+			if err == nil || err.Error() != `unexpected input instead of EOF: parse error at 1:2 near ⟪2⟫⏵⟪+3⟫` {
+				t.Errorf("%v: ParseStart GoalVal error: %v", mode, err)
+			}
+			_, err = p.ParseStart(nSum, NewState([]byte("2+3")))
+			if !errors.Is(err, ErrStart) || errors.Is(err, ErrDefine) || err.Error() != "Sum: not a start non-terminal" {
+				t.Errorf("%v: ParseStart Sum error: %v", mode, err)
+			}
+		}
+	}
+}
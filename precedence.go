@@ -0,0 +1,126 @@
+package lr0
+
+import (
+	"github.com/pkg/errors"
+)
+
+// Left declares left-associative terminals with the same precedence level,
+// like `%left` in yacc.
+//
+// Every next declaration of Left, Right or NonAssoc has higher precedence than
+// previous ones. Precedence is used to resolve shift-reduce conflicts while
+// building the table: a terminal to shift is compared with a rule to reduce.
+// Precedence of a rule is precedence of its last terminal, unless it's set by
+// NonTerminal.Prec().
+//
+//	New(terminals, rules,
+//		Left(tPlus, tMinus),
+//		Left(tMul, tDiv),
+//		Right(tPow),
+//	)
+//
+// With equal precedence left-associative terminal will reduce `a+b` before
+// shifting the next `+`.
+func Left(ids ...Id) Option { return declarePrecedence(assocLeft, ids) }
+
+// Right declares right-associative terminals with the same precedence level,
+// like `%right` in yacc. See Left.
+//
+// With equal precedence right-associative terminal will be shifted, so
+// `a^b^c` means `a^(b^c)`.
+func Right(ids ...Id) Option { return declarePrecedence(assocRight, ids) }
+
+// NonAssoc declares non-associative terminals with the same precedence level,
+// like `%nonassoc` in yacc. See Left.
+//
+// With equal precedence non-associative terminal will cause parse error, so
+// `a<b<c` is error.
+func NonAssoc(ids ...Id) Option { return declarePrecedence(assocNon, ids) }
+
+type associativity int
+
+const (
+	assocLeft associativity = iota
+	assocRight
+	assocNon
+)
+
+type precedence struct {
+	level int
+	assoc associativity
+}
+
+func declarePrecedence(assoc associativity, ids []Id) Option {
+	return func(c *config) {
+		if c.precedence == nil {
+			c.precedence = make(map[Id]precedence)
+		}
+		c.precLevels++
+		for _, id := range ids {
+			c.precedence[id] = precedence{level: c.precLevels, assoc: assoc}
+		}
+	}
+}
+
+// precedenceRule is implemented by Rule with explicit precedence
+type precedenceRule interface {
+	// Precedence returns Id which precedence to use for the Rule or InvalidId
+	Precedence() Id
+}
+
+type shiftReduceResolution int
+
+const (
+	// no precedence for terminal or rule
+	resolveUnknown shiftReduceResolution = iota
+	resolveShift
+	resolveReduce
+	resolveError
+)
+
+// resolveShiftReduce decides by precedence what to do with shift-reduce
+// conflict of terminal `id` and Rule `r`
+func (c *config) resolveShiftReduce(id Id, r Rule, g *grammar) shiftReduceResolution {
+	tp, ok := c.precedence[id]
+	if !ok {
+		return resolveUnknown
+	}
+	rp, ok := c.rulePrecedence(r, g)
+	if !ok {
+		return resolveUnknown
+	}
+	switch {
+	case tp.level > rp.level:
+		return resolveShift
+	case tp.level < rp.level:
+		return resolveReduce
+	}
+	switch tp.assoc {
+	case assocLeft:
+		return resolveReduce
+	case assocRight:
+		return resolveShift
+	default:
+		return resolveError
+	}
+}
+
+func (c *config) rulePrecedence(r Rule, g *grammar) (precedence, bool) {
+	if pr, ok := r.(precedenceRule); ok {
+		if id := pr.Precedence(); id != InvalidId {
+			p, ok := c.precedence[id]
+			if !ok {
+				panic(errors.Wrapf(ErrDefine, "no precedence declared for %s used in rule %s", dumpId(id, g), r))
+			}
+			return p, true
+		}
+	}
+	def := r.Definition()
+	for i := len(def) - 1; i >= 0; i-- {
+		if g.IsTerminal(def[i]) {
+			p, ok := c.precedence[def[i]]
+			return p, ok
+		}
+	}
+	return precedence{}, false
+}
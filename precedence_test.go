@@ -0,0 +1,125 @@
+package lr0
+
+import (
+	"fmt"
+	"testing"
+	"unicode"
+
+	"github.com/vovan-ve/go-lr0-parser/internal/testutils"
+)
+
+const (
+	tPow Id = nGoal + 1 + iota
+	tLess
+	tUMinus
+)
+
+func TestPrecedence(t *testing.T) {
+	// Goal : E $
+	// E    : E "+" E | E "-" E | E "*" E | E "/" E | E "^" E | E "<" E
+	//      | "-" E %prec UMINUS | int
+	g := newGrammar(
+		[]Terminal{
+			NewTerm(tInt, "int").FuncByte(isDigit, bytesToInt),
+			NewTerm(tPlus, `"+"`).Hide().Str("+"),
+			NewTerm(tMinus, `"-"`).Hide().Str("-"),
+			NewTerm(tMul, `"*"`).Hide().Str("*"),
+			NewTerm(tDiv, `"/"`).Hide().Str("/"),
+			NewTerm(tPow, `"^"`).Hide().Str("^"),
+			NewTerm(tLess, `"<"`).Hide().Str("<"),
+			NewWhitespace().FuncRune(unicode.IsSpace),
+		},
+		[]NonTerminalDefinition{
+			NewNT(nGoal, "Goal").Main().Is(nSum),
+			NewNT(nSum, "E").
+				Is(nSum, tPlus, nSum).Do(func(a, b string) string { return "(" + a + "+" + b + ")" }).
+				Is(nSum, tMinus, nSum).Do(func(a, b string) string { return "(" + a + "-" + b + ")" }).
+				Is(nSum, tMul, nSum).Do(func(a, b string) string { return "(" + a + "*" + b + ")" }).
+				Is(nSum, tDiv, nSum).Do(func(a, b string) string { return "(" + a + "/" + b + ")" }).
+				Is(nSum, tPow, nSum).Do(func(a, b string) string { return "(" + a + "^" + b + ")" }).
+				Is(nSum, tLess, nSum).Do(func(a, b string) string { return "(" + a + "<" + b + ")" }).
+				Is(tMinus, nSum).Prec(tUMinus).Do(func(a string) string { return "(-" + a + ")" }).
+				Is(tInt).Do(func(v int) string { return fmt.Sprint(v) }),
+		},
+	)
+	opts := []Option{
+		NonAssoc(tLess),
+		Left(tPlus, tMinus),
+		Left(tMul, tDiv),
+		Right(tUMinus),
+		Right(tPow),
+	}
+
+	for _, mode := range []TableMode{LR0, SLR1, LALR1, LR1} {
+		p := newParser(g, append(opts, WithMode(mode))...)
+		for input, result := range map[string]string{
+			"1+2*3":      "(1+(2*3))",
+			"1*2+3":      "((1*2)+3)",
+			"1-2-3":      "((1-2)-3)",
+			"1/2*3":      "((1/2)*3)",
+			"2^3^4":      "(2^(3^4))",
+			"-2^2":       "(-(2^2))",
+			"-2*3":       "((-2)*3)",
+			"1 - -2 - 3": "((1-(-2))-3)",
+			"1+2 < 3*4":  "((1+2)<(3*4))",
+			"2*3^-4^5+6": "((2*(3^(-(4^5))))+6)",
+		} {
+			t.Run(fmt.Sprintf("%v: %s", mode, input), func(t *testing.T) {
+				v, err := p.Parse(NewState([]byte(input)))
+				if err != nil {
+					t.Fatal("parse failed:", err)
+				}
+				if v != result {
+					t.Fatalf("result is %#v", v)
+				}
+			})
+		}
+
+		_, err := p.Parse(NewState([]byte("1 < 2 < 3")))
+		if err == nil || err.Error() != `unexpected input: "<" is non-associative: parse error at 1:6 near ⟪1␠<␠2⟫⏵⟪␠<␠3⟫` {
+			t.Errorf("%v: wrong error: %v", mode, err)
+		}
+	}
+
+	t.Run("conflict without precedence", func(t *testing.T) {
+		defer testutils.ExpectPanicError(t, ErrConflictShiftReduce)
+		newTable(g, WithMode(LALR1), Left(tPlus, tMinus), Right(tUMinus))
+	})
+
+	t.Run("undeclared prec", func(t *testing.T) {
+		defer testutils.ExpectPanicError(t, ErrDefine, func(t *testing.T, err error) {
+			const expected = "no precedence declared for #16 used in rule E : \"-\" E: invalid definition"
+			if err.Error() != expected {
+				t.Error("wrong error message:", err)
+			}
+		})
+		newTable(g, WithMode(SLR1), Left(tPlus, tMinus, tMul, tDiv, tPow, tLess))
+	})
+}
+
+func TestPrecedence_NonAssocOnly(t *testing.T) {
+	// Goal : E $
+	// E    : E "<" E | int
+	g := newGrammar(
+		[]Terminal{
+			NewTerm(tInt, "int").FuncByte(isDigit, bytesToInt),
+			NewTerm(tLess, `"<"`).Hide().Str("<"),
+		},
+		[]NonTerminalDefinition{
+			NewNT(nGoal, "Goal").Main().Is(nSum),
+			NewNT(nSum, "E").
+				Is(nSum, tLess, nSum).Do(func(a, b string) string { return "(" + a + "<" + b + ")" }).
+				Is(tInt).Do(func(v int) string { return fmt.Sprint(v) }),
+		},
+	)
+	for _, mode := range []TableMode{LR0, SLR1, LALR1, LR1} {
+		p := newParser(g, WithMode(mode), NonAssoc(tLess))
+		if v, err := p.Parse(NewState([]byte("1<2"))); err != nil || v != "(1<2)" {
+			t.Errorf("%v: result %#v, %v", mode, v, err)
+		}
+		v, err := p.Parse(NewState([]byte("1<2<3")))
+		if err == nil || err.Error() != `unexpected input: "<" is non-associative: parse error at 1:4 near ⟪1<2⟫⏵⟪<3⟫` {
+			t.Errorf("%v: result %#v, wrong error: %v", mode, v, err)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package lr0
+
+// recoveryShifts is count of terminals to shift after recovery before the next
+// error will be recorded
+const recoveryShifts = 3
+
+// recovery is a state of panic-mode error recovery with Error symbol like yacc
+// does. The nil *recovery means recovery is disabled, so errors are returned
+// as is.
+//
+// On syntax error the stack is unwound to a state which can shift Error, and
+// Error is shifted with the error as its value. Then tokens which cannot
+// continue are skipped. Errors are not recorded until a few terminals will
+// be shifted, so a single mistake does not cause a cascade of errors.
+type recovery struct {
+	// errs are recorded errors
+	errs []error
+	// shifts is count of terminals left to shift to finish recovery
+	shifts int
+}
+
+// shifted counts a terminal shifted
+func (r *recovery) shifted() {
+	if r != nil && r.shifts > 0 {
+		r.shifts--
+	}
+}
+
+// recover tries to recover from the error at the token starting with the
+// given State. It returns true when the token must be tried again with the
+// Error shifted, or false when the token must be skipped. Fatal error is
+// returned when recovery is impossible.
+func (r *recovery) recover(st *stack, err error, token *State, eof bool) (retry bool, fatal error) {
+	if r == nil {
+		return false, err
+	}
+	recorded := r.shifts == 0
+	if recorded {
+		r.errs = append(r.errs, err)
+	}
+	fail := func() error {
+		if recorded {
+			return ParseErrors(r.errs)
+		}
+		return r.fatal(err)
+	}
+
+	if r.shifts == recoveryShifts {
+		// nothing was shifted since the previous recovery, so the token would
+		// fail again in the same way
+		if eof {
+			return false, fail()
+		}
+		return false, nil
+	}
+	to, ok := st.unwind(tError)
+	if !ok {
+		return false, fail()
+	}
+	st.ShiftAt(to, tError, err, token, token)
+	r.shifts = recoveryShifts
+	return true, nil
+}
+
+// fatal returns the given error which stops parsing after all recorded errors.
+// The error is returned as is when nothing was recorded.
+func (r *recovery) fatal(err error) error {
+	if r == nil || len(r.errs) == 0 {
+		return err
+	}
+	return ParseErrors(append(r.errs, err))
+}
+
+// result returns recorded errors when parsing is done
+func (r *recovery) result() error {
+	if r == nil || len(r.errs) == 0 {
+		return nil
+	}
+	return ParseErrors(r.errs)
+}
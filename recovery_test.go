@@ -0,0 +1,174 @@
+package lr0
+
+import (
+	"fmt"
+	"testing"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+func TestParser_Recovery(t *testing.T) {
+	const (
+		tSemicolon = nGoal + iota + 1
+		nStmt
+	)
+	type testCase struct {
+		input  string
+		result string
+		errs   []string
+	}
+	for _, mode := range []TableMode{LR0, SLR1, LALR1, LR1} {
+		p := New(
+			[]Terminal{
+				NewTerm(tInt, "int").FuncByte(isDigit, bytesToInt),
+				NewTerm(tPlus, `"+"`).Hide().Str("+"),
+				NewTerm(tSemicolon, `";"`).Hide().Str(";"),
+				NewWhitespace().FuncRune(unicode.IsSpace),
+			},
+			[]NonTerminalDefinition{
+				NewNT(nGoal, "Goal").Main().Is(OneOrMore(nStmt)),
+				NewNT(nStmt, "Stmt").
+					Is(nSum, tSemicolon).
+					Is(Error, tSemicolon).Do(func(err error) (string, error) {
+					if !errors.Is(err, ErrParse) {
+						return "", errors.Wrap(err, "not a parse error")
+					}
+					return "E", nil
+				}),
+				NewNT(nSum, "Sum").
+					Is(nSum, tPlus, tInt).Do(calc2IntSum).
+					Is(tInt),
+			},
+			WithMode(mode),
+		)
+		for _, c := range []testCase{
+			{input: "1+2; 3;", result: "[3 3]"},
+			{
+				input:  "1+;2;",
+				result: "[E 2]",
+				errs:   []string{"unexpected input: expected int: parse error at 1:3 near ⟪1+⟫⏵⟪;2;⟫"},
+			},
+			{
+				input:  "1 2 3; 4;",
+				result: "[E 4]",
+				errs:   []string{`unexpected input: expected "+" or ";": parse error at 1:2 near ⟪1⟫⏵⟪␠2␠3;␠4;⟫`},
+			},
+			{
+				input:  "1+;2 2;3+;4;",
+				result: "[E E E 4]",
+				// errors right after recovery are not recorded
+				errs: []string{
+					"unexpected input: expected int: parse error at 1:3 near ⟪1+⟫⏵⟪;2␠2;3+;4;⟫",
+					"unexpected input: expected int: parse error at 1:10 near ⟪1+;2␠2;3+⟫⏵⟪;4;⟫",
+				},
+			},
+			{
+				input:  "1+;2+3 2;3+;4;",
+				result: "[E E E 4]",
+				errs: []string{
+					"unexpected input: expected int: parse error at 1:3 near ⟪1+⟫⏵⟪;2+3␠2;3+;4;⟫",
+					`unexpected input: expected "+" or ";": parse error at 1:7 near ⟪1+;2+3⟫⏵⟪␠2;3+;4;⟫`,
+					"unexpected input: expected int: parse error at 1:12 near ⟪1+;2+3␠2;3+⟫⏵⟪;4;⟫",
+				},
+			},
+			{
+				input:  "1 # 2; 3;",
+				result: "[E 3]",
+				errs:   []string{`unexpected input: expected "+" or ";": parse error at 1:3 near ⟪1␠⟫⏵⟪#␠2;␠3;⟫`},
+			},
+			{
+				input: "1; 2+",
+				errs: []string{
+					"unexpected input: expected int: parse error at 1:6 near ⟪1;␠2+⟫⏵<EOF>",
+					`unexpected input: expected ";": parse error at 1:6 near ⟪1;␠2+⟫⏵<EOF>`,
+				},
+			},
+		} {
+			t.Run(fmt.Sprintf("%v: %s", mode, c.input), func(t *testing.T) {
+				v, err := p.Parse(NewState([]byte(c.input)))
+				if v == nil {
+					if c.result != "" {
+						t.Errorf("no result, expected %s", c.result)
+					}
+				} else if s := fmt.Sprint(v); s != c.result {
+					t.Errorf("result %s, expected %s", s, c.result)
+				}
+				if len(c.errs) == 0 {
+					if err != nil {
+						t.Fatal(err)
+					}
+					return
+				}
+				if !errors.Is(err, ErrParse) {
+					t.Fatal("another error:", err)
+				}
+				var list ParseErrors
+				if !errors.As(err, &list) {
+					t.Fatalf("not a list: %#v", err)
+				}
+				if len(list) != len(c.errs) {
+					t.Fatalf("errors:\n%v", err)
+				}
+				for i, e := range list {
+					if e.Error() != c.errs[i] {
+						t.Errorf("error %d: %v", i, e)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestParser_RecoveryEach(t *testing.T) {
+	const (
+		tSemicolon = nGoal + iota + 1
+		nStmt
+	)
+	for _, mode := range []TableMode{LR0, SLR1, LALR1, LR1} {
+		p := New(
+			[]Terminal{
+				NewTerm(tInt, "int").FuncByte(isDigit, bytesToInt),
+				NewTerm(tPlus, `"+"`).Hide().Str("+"),
+				NewTerm(tSemicolon, `";"`).Hide().Str(";"),
+				NewWhitespace().FuncRune(unicode.IsSpace),
+			},
+			[]NonTerminalDefinition{
+				NewNT(nGoal, "Goal").Main().Is(nStmt),
+				NewNT(nStmt, "Stmt").
+					Is(nSum, tSemicolon).
+					Is(Error, tSemicolon).Do(func(error) string { return "E" }),
+				NewNT(nSum, "Sum").
+					Is(nSum, tPlus, tInt).Do(calc2IntSum).
+					Is(tInt),
+			},
+			WithMode(mode),
+		)
+		t.Run(fmt.Sprint(mode), func(t *testing.T) {
+			var results []any
+			err := p.ParseEach(NewState([]byte("1+2; 3+; 4 5; 6;")), func(result any, _ *State) error {
+				results = append(results, result)
+				return nil
+			})
+			if s := fmt.Sprint(results); s != "[3 E E 6]" {
+				t.Errorf("results %s", s)
+			}
+			var list ParseErrors
+			if !errors.As(err, &list) {
+				t.Fatalf("not a list: %#v", err)
+			}
+			expected := []string{
+				"unexpected input: expected int: parse error at 1:8 near ⟪1+2;␠3+⟫⏵⟪;␠4␠5;␠6;⟫",
+				`unexpected input: expected "+" or ";": parse error at 1:11 near ⟪1+2;␠3+;␠4⟫⏵⟪␠5;␠6;⟫`,
+			}
+			if len(list) != len(expected) {
+				t.Fatalf("errors:\n%v", err)
+			}
+			for i, e := range list {
+				if e.Error() != expected[i] {
+					t.Errorf("error %d: %v", i, e)
+				}
+			}
+		})
+	}
+}
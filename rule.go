@@ -1,22 +1,23 @@
 package lr0
 
 import (
+	"reflect"
+
 	"github.com/pkg/errors"
 )
 
 func newRule(s Symbol, main bool, d nonTerminalDefinition, l NamedHiddenRegistry) *rule {
-	defer func() {
-		e := recover()
-		if e == nil {
-			return
-		}
-		err, ok := e.(error)
-		if !ok || !errors.Is(err, ErrDefine) {
-			panic(e)
-		}
-		panic(errors.Wrapf(err, "rule for %s", dumpSymbol(s)))
-	}()
+	r, err := newRuleE(s, main, d, l)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
 
+// newRuleE creates new rule like newRule does, but returns definition error
+// instead of panic. The rule is returned anyway without calc func to let
+// check the rest of grammar.
+func newRuleE(s Symbol, main bool, d nonTerminalDefinition, l NamedHiddenRegistry) (*rule, error) {
 	hidden := make(map[int]struct{})
 	for i, id := range d.items {
 		if l.IsHidden(id) {
@@ -24,14 +25,32 @@ func newRule(s Symbol, main bool, d nonTerminalDefinition, l NamedHiddenRegistry
 		}
 	}
 
-	return &rule{
+	r := &rule{
 		subject:    s.Id(),
 		eof:        main,
 		definition: d.items,
-		calc:       newCalcFunc(d.calcHandler, len(d.items)-len(hidden)),
 		hidden:     hidden,
+		prec:       d.prec,
 		nameReg:    l,
 	}
+	err := catchDefine(func() {
+		argsCount := len(d.items) - len(hidden)
+		if calcWantsSpan(d.calcHandler, argsCount) {
+			r.span = true
+			argsCount++
+		}
+		if len(d.items) == 0 && isNilHandler(d.calcHandler) {
+			// empty definition gives nil without Do
+			r.calc = calcDefaultNil
+		} else {
+			r.calc = newCalcFunc(d.calcHandler, argsCount)
+		}
+		r.argTypes, r.resultType = calcTypes(d.calcHandler)
+	})
+	if err != nil {
+		return r, errors.Wrapf(err, "rule for %s", dumpSymbol(s))
+	}
+	return r, nil
 }
 
 type rule struct {
@@ -40,18 +59,28 @@ type rule struct {
 	definition []Id
 	calc       calcFunc
 	hidden     map[int]struct{}
+	prec       Id
 	nameReg    SymbolRegistry
+	// span is set when calc takes Span as the first argument
+	span bool
+	// argTypes and resultType are types of Do handler, nil without Do
+	argTypes   []reflect.Type
+	resultType reflect.Type
 }
 
 func (r *rule) Subject() Id      { return r.subject }
 func (r *rule) HasEOF() bool     { return r.eof }
 func (r *rule) Definition() []Id { return r.definition }
+func (r *rule) Precedence() Id   { return r.prec }
 
 func (r *rule) String() string {
 	s := dumpId(r.subject, r.nameReg) + " :"
 	for _, id := range r.definition {
 		s += " " + dumpId(id, r.nameReg)
 	}
+	if len(r.definition) == 0 {
+		s += " ε"
+	}
 	if r.eof {
 		s += " $"
 	}
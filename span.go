@@ -0,0 +1,59 @@
+package lr0
+
+import (
+	"reflect"
+)
+
+// Span is a part of source where a non-terminal was found
+//
+// A Do handler can take Span as the first argument before values:
+//
+//	NewNT(nVar, "Var").
+//		Is(tIdent).Do(func(sp Span, name string) (int, error) {
+//			v, ok := vars[name]
+//			if !ok {
+//				return 0, fmt.Errorf("undefined variable %s at %s", name, sp)
+//			}
+//			return v, nil
+//		})
+//
+// Span of an empty definition is empty right after the previous symbol.
+type Span struct {
+	// Start is position of the first byte
+	Start *State
+	// End is position after the last byte
+	End *State
+}
+
+var typeOfSpan = reflect.TypeOf(Span{})
+
+// Bytes returns bytes of source in the Span, or nil if they were discarded
+// from stream, see NewReaderState
+func (s Span) Bytes() []byte {
+	if s.Start == nil || s.End == nil {
+		return nil
+	}
+	return s.Start.bytesToIfKept(s.End)
+}
+
+// String returns `line:column` of the Span start, or `file:line:column` for
+// named source
+func (s Span) String() string {
+	if s.Start == nil {
+		return "?"
+	}
+	return s.Start.Position().String()
+}
+
+// calcWantsSpan checks if the given Do handler takes Span as the first
+// argument before the given count of values
+func calcWantsSpan(fn any, valuesCount int) bool {
+	if h, ok := fn.(Handler); ok {
+		return h.args == valuesCount+1 && h.in[0] == typeOfSpan
+	}
+	funcT := reflect.TypeOf(fn)
+	return funcT != nil &&
+		funcT.Kind() == reflect.Func &&
+		funcT.NumIn() == valuesCount+1 &&
+		funcT.In(0) == typeOfSpan
+}
@@ -0,0 +1,60 @@
+package lr0
+
+import (
+	"fmt"
+	"testing"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+func TestSpan(t *testing.T) {
+	vars := map[string]int{"foo": 42}
+	var spans []string
+	p := New(
+		[]Terminal{
+			NewTerm(tInt, "int").FuncByte(isDigit, bytesToInt),
+			NewTerm(tIdent, "ident").Type(typeOfString).Func(matchIdentifier),
+			NewTerm(tPlus, `"+"`).Hide().Str("+"),
+			NewTerm(tMinus, `"-"`).Str("-"),
+			NewWhitespace().FuncRune(unicode.IsSpace),
+		},
+		[]NonTerminalDefinition{
+			NewNT(nGoal, "Goal").Main().Is(nSum),
+			NewNT(nSum, "Sum").
+				Is(nSum, tPlus, nVal).Do(func(sp Span, a, b int) int {
+				spans = append(spans, fmt.Sprintf("%s %q", sp, sp.Bytes()))
+				return a + b
+			}).
+				Is(nVal),
+			NewNT(nVal, "Val").
+				Is(tInt).
+				Is(Optional(tMinus), tIdent).Do(Do3E(func(sp Span, _, name string) (int, error) {
+				v, ok := vars[name]
+				if !ok {
+					return 0, errors.Wrapf(ErrParse, "undefined variable %s at %s", name, sp)
+				}
+				return v, nil
+			})),
+		},
+	)
+
+	v, err := p.Parse(NewState([]byte("1 +\n  foo+\n -  foo ")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != 85 {
+		t.Errorf("result %v", v)
+	}
+	if s := fmt.Sprint(spans); s != `[1:1 "1 +\n  foo" 1:1 "1 +\n  foo+\n -  foo"]` {
+		t.Errorf("spans %s", s)
+	}
+
+	_, err = p.Parse(NewState([]byte("1 +\n  bar")))
+	if !errors.Is(err, ErrParse) {
+		t.Fatal("another error:", err)
+	}
+	if s := err.Error(); s != "undefined variable bar at 2:3: parse error at 2:6 near ⟪1␠+␊␠␠bar⟫⏵<EOF>" {
+		t.Errorf("error %q", s)
+	}
+}
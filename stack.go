@@ -10,20 +10,26 @@ import (
 //	Current() Row
 //	// Shift does shift - push the given item into Stack
 //	Shift(si tableStateIndex, id Id, value any)
+//	// ShiftAt does the same as Shift for Terminal matched in source from
+//	// start to end
+//	ShiftAt(si tableStateIndex, id Id, value any, start, end *State)
 //	// Reduce tries to perform reduce in current state. If no ReduceRule
 //	// available, returns `false, nil`. If an error occurred while calculating
 //	// a value, `false, error` will be returned. Of success `true, nil` will be
 //	// returned.
 //	Reduce() (bool, error)
+//	// ReduceFor does the same as Reduce, but with a rule for the given
+//	// lookahead terminal
+//	ReduceFor(lookahead Id) (bool, error)
 //	// Done ends work, returns the final value from Stack and resets Stack to
 //	// initial state
 //	Done() any
 //}
 
 // newStack creates new stack for the given Table
-func newStack(t *table) *stack {
-	st := &stack{t: t}
-	st.set(0)
+func newStack(t *table, start tableStateIndex) *stack {
+	st := &stack{t: t, start: start}
+	st.set(start)
 	return st
 }
 
@@ -31,23 +37,58 @@ type stack struct {
 	t     *table
 	items []stackItem
 	si    tableStateIndex
-	// cached `.t.Row(.si)`
+	// start is the initial state of the main rule being parsed
+	start tableStateIndex
+	// cached `.t.rows[.si]`
 	row *tableRow
+	// tree is set to build Node tree instead of calling Do handlers, see
+	// WithTree
+	tree       bool
+	treeHidden bool
+	// origin is the start of input
+	origin *State
+	// marked is set to restore items later, see mark
+	marked *stackMark
+}
+
+// stackMark is a place in the stack to restore it later. Items are saved only
+// when they are removed, so the stack is not copied.
+type stackMark struct {
+	// low is the least count of items since the mark, so items below it
+	// are kept as is
+	low int
+	// saved are removed items from low up to count of items at the mark, in
+	// reverse order
+	saved []stackItem
 }
 
 func (s *stack) Current() *tableRow { return s.row }
 
 func (s *stack) Shift(si tableStateIndex, id Id, value any) {
-	s.set(si)
-	s.items = append(s.items, stackItem{
-		state: si,
-		node:  id,
-		value: value,
-	})
+	s.ShiftAt(si, id, value, nil, nil)
+}
+
+func (s *stack) ShiftAt(si tableStateIndex, id Id, value any, start, end *State) {
+	if s.tree {
+		value = newLeafNode(id, s.t.g, start, end)
+	}
+	s.push(stackItem{state: si, node: id, value: value, start: start, end: end})
+}
+
+func (s *stack) push(it stackItem) {
+	s.set(it.state)
+	s.items = append(s.items, it)
 }
 
 func (s *stack) Reduce() (bool, error) {
-	r := s.row.ReduceRule()
+	return s.reduce(s.row.ReduceRule())
+}
+
+func (s *stack) ReduceFor(lookahead Id) (bool, error) {
+	return s.reduce(s.row.ReduceRuleFor(lookahead))
+}
+
+func (s *stack) reduce(r Rule) (bool, error) {
 	if r == nil {
 		return false, nil
 	}
@@ -59,7 +100,12 @@ func (s *stack) Reduce() (bool, error) {
 	}
 	nextCount := totalCount - reduceCount
 
-	values := make([]any, 0, reduceCount)
+	start, end := s.span(nextCount)
+
+	values := make([]any, 0, reduceCount+1)
+	if rr, ok := r.(*rule); ok && rr.span {
+		values = append(values, Span{Start: start, End: end})
+	}
 	def := r.Definition()
 	for i, it := range s.items[nextCount:] {
 		if it.node != def[i] {
@@ -69,16 +115,21 @@ func (s *stack) Reduce() (bool, error) {
 			values = append(values, it.value)
 		}
 	}
-	newValue, err := r.Value(values)
-	if err != nil {
-		return false, err
+	var newValue any
+	if s.tree {
+		newValue = newRuleNode(r, s.items[nextCount:], s.t.g, s.treeHidden, start, end)
+	} else {
+		var err error
+		if newValue, err = r.Value(values); err != nil {
+			return false, err
+		}
 	}
 
-	var baseSI tableStateIndex
+	baseSI := s.start
 	if totalCount > reduceCount {
 		baseSI = s.items[nextCount-1].state
 	}
-	baseRow := s.t.Row(baseSI)
+	baseRow := s.t.rows[baseSI]
 
 	newId := r.Subject()
 	newSI, ok := baseRow.GotoAction(newId)
@@ -86,28 +137,150 @@ func (s *stack) Reduce() (bool, error) {
 		panic(errors.Wrap(ErrInternal, "unexpected state in gotos"))
 	}
 
-	s.items = s.items[:nextCount]
-	s.Shift(newSI, newId, newValue)
+	s.truncate(nextCount)
+	s.push(stackItem{state: newSI, node: newId, value: newValue, start: start, end: end})
 	return true, nil
 }
 
+// unwind pops items until the current state can shift the given terminal, and
+// returns the state to shift to. It returns false when no state in the stack
+// can shift it.
+func (s *stack) unwind(id Id) (tableStateIndex, bool) {
+	for {
+		if to, ok := s.row.TerminalAction(id); ok {
+			return to, true
+		}
+		n := len(s.items)
+		if n == 0 {
+			return 0, false
+		}
+		s.truncate(n - 1)
+		if n == 1 {
+			s.set(s.start)
+		} else {
+			s.set(s.items[n-2].state)
+		}
+	}
+}
+
+// acceptsEof returns true when the input can end here, so reduces by EOF lead
+// to accept. The stack is not changed.
+func (s *stack) acceptsEof() bool {
+	// the stack is simulated as its first `depth` items followed by `pushed`
+	// states, so it is not copied
+	var (
+		depth  = len(s.items)
+		pushed []tableStateIndex
+		row    = s.row
+	)
+	for {
+		r := row.ReduceRuleFor(tEof)
+		if r == nil {
+			if row.AcceptEof() {
+				return true
+			}
+			if r = row.ReduceRule(); r == nil {
+				return false
+			}
+		}
+		pop := len(r.Definition())
+		if pop <= len(pushed) {
+			pushed = pushed[:len(pushed)-pop]
+		} else {
+			depth -= pop - len(pushed)
+			pushed = pushed[:0]
+			if depth < 0 {
+				return false
+			}
+		}
+		base := s.start
+		if len(pushed) != 0 {
+			base = pushed[len(pushed)-1]
+		} else if depth != 0 {
+			base = s.items[depth-1].state
+		}
+		to, ok := s.t.rows[base].GotoAction(r.Subject())
+		if !ok {
+			return false
+		}
+		pushed = append(pushed, to)
+		row = s.t.rows[to]
+	}
+}
+
+// mark remembers the current items to restore them later by restore. The
+// previous mark is dropped.
+func (s *stack) mark() {
+	s.marked = &stackMark{low: len(s.items)}
+}
+
+// restore returns items to the last mark and drops it
+func (s *stack) restore() {
+	m := s.marked
+	s.marked = nil
+	s.items = s.items[:m.low]
+	for i := len(m.saved) - 1; i >= 0; i-- {
+		s.items = append(s.items, m.saved[i])
+	}
+	if n := len(s.items); n == 0 {
+		s.set(s.start)
+	} else {
+		s.set(s.items[n-1].state)
+	}
+}
+
+// truncate removes items above the given count saving ones needed by the mark
+func (s *stack) truncate(n int) {
+	if m := s.marked; m != nil {
+		for ; m.low > n; m.low-- {
+			m.saved = append(m.saved, s.items[m.low-1])
+		}
+	}
+	s.items = s.items[:n]
+}
+
+// span returns source span of items from the given index
+//
+// Empty items are skipped, so whitespaces around them are not included. Empty
+// span is placed right after the previous item.
+func (s *stack) span(from int) (start, end *State) {
+	for _, it := range s.items[from:] {
+		if it.start == nil || it.start.Offset() == it.end.Offset() {
+			continue
+		}
+		if start == nil {
+			start = it.start
+		}
+		end = it.end
+	}
+	if start == nil {
+		end = s.origin
+		if from != 0 {
+			end = s.items[from-1].end
+		}
+		start = end
+	}
+	return
+}
+
 func (s *stack) Done() any {
 	if len(s.items) != 1 {
 		panic(errors.Wrap(ErrInternal, "unexpected stack content"))
 	}
 	v := s.items[0].value
 	s.items = nil
-	s.set(0)
+	s.set(s.start)
 	return v
 }
 
 func (s *stack) set(si tableStateIndex) {
-	s.si, s.row = si, s.t.Row(si)
+	s.si, s.row = si, s.t.rows[si]
 }
 
 type stackItem struct {
 	state tableStateIndex
 	node  Id
 	value any
-	// at *State - can be useful or not - then complicated calc api
+	// start and end of the item in source, nil when unknown
+	start, end *State
 }
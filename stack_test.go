@@ -1,6 +1,7 @@
 package lr0
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -98,7 +99,7 @@ func TestStack(t *testing.T) {
 		},
 	))
 
-	st := newStack(testTable)
+	st := newStack(testTable, 0)
 	if st.si != 0 {
 		t.Fatal("initial state ", st.si)
 	}
@@ -237,3 +238,66 @@ func TestStack(t *testing.T) {
 		t.Errorf("result items: %#v", st.items)
 	}
 }
+
+func TestStack_Mark(t *testing.T) {
+	testTable := newTable(newGrammar(
+		[]Terminal{
+			NewTerm(tZero, "zero").Str("0"),
+			NewTerm(tOne, "one").Str("1"),
+			NewTerm(tPlus, `"+"`).Str("+"),
+		},
+		[]NonTerminalDefinition{
+			NewNT(nGoal, "Goal").Main().Is(nSum),
+			NewNT(nSum, "Sum").
+				Is(nSum, tPlus, nVal).Do(calc3StrTrace).
+				Is(nVal),
+			NewNT(nVal, "Val").
+				Is(tZero).
+				Is(tOne),
+		},
+	))
+	shift := func(st *stack, id Id, v string) {
+		t.Helper()
+		to, ok := st.Current().TerminalAction(id)
+		if !ok {
+			t.Fatalf("cannot shift %v", id)
+		}
+		st.Shift(to, id, v)
+	}
+	reduce := func(st *stack) {
+		t.Helper()
+		if ok, err := st.Reduce(); !ok || err != nil {
+			t.Fatalf("reduce %v, %v", ok, err)
+		}
+	}
+
+	// 1+0 is marked, then reduced to Sum and continued with +1
+	st := newStack(testTable, 0)
+	shift(st, tOne, "1")
+	reduce(st)
+	reduce(st)
+	shift(st, tPlus, "+")
+	shift(st, tZero, "0")
+	expect := append([]stackItem(nil), st.items...)
+	si := st.si
+
+	st.mark()
+	reduce(st)
+	reduce(st)
+	shift(st, tPlus, "+")
+	shift(st, tOne, "1")
+	if len(st.items) != 3 || st.items[0].value != "(1 + 0)" {
+		t.Fatalf("items: %#v", st.items)
+	}
+
+	st.restore()
+	if !reflect.DeepEqual(st.items, expect) {
+		t.Errorf("restored items: %#v", st.items)
+	}
+	if st.si != si {
+		t.Errorf("restored state %v, expected %v", st.si, si)
+	}
+	if st.marked != nil {
+		t.Error("mark is kept")
+	}
+}
@@ -0,0 +1,154 @@
+package lr0
+
+import (
+	"io"
+	"math"
+	"unicode/utf8"
+)
+
+// maxOffset is offset to read the whole stream
+const maxOffset = math.MaxInt
+
+// streamReadSize is minimal size of one read from stream
+const streamReadSize = 4096
+
+// streamKeepBefore is count of bytes to keep before the current position of
+// parser to show them in errors
+const streamKeepBefore = stateFormatContext * utf8.UTFMax
+
+// NewReaderState creates new State reading the given stream on demand
+//
+// Unlike NewState, the whole input is not necessary in memory. Parse
+// discards data behind the current token except few bytes for error
+// messages, so memory is bounded by the longest token. Values of Terminals
+// are not affected, but Bytes of Span and Node for data already discarded
+// are nil.
+//
+// States never panic and never change, but a State far behind the parser,
+// like Span.Start of a long rule or `end` of ParseEach kept for later, can
+// become stale. It keeps only the part of stream read around it, so it sees
+// the input ending where the kept data ends: IsEOF returns true there, and
+// RestBytes, BytesTo and String are truncated. Its position is right anyway.
+//
+// Read error other than io.EOF stops the input like EOF, and Parse returns
+// it. State from NewReaderState must not be used concurrently.
+//
+//	f, err := os.Open("huge.log")
+//	...
+//	result, err := parser.Parse(NewReaderState("huge.log", f))
+func NewReaderState(name string, r io.Reader) *State {
+	st := &stream{r: r}
+	st.cur = &streamChunk{s: st, line: 1, column: 1}
+	return &State{
+		file:  &sourceFile{name: name, tabWidth: 1},
+		chunk: st.cur,
+	}
+}
+
+// stream reads data from io.Reader into chunks. When data before `keep`
+// offset becomes big enough, a new chunk is started from `keep`, so the
+// previous chunk can be freed when no State refers it.
+type stream struct {
+	r    io.Reader
+	cur  *streamChunk
+	eof  bool
+	err  error
+	keep int
+}
+
+// streamChunk is a part of stream starting at `start` offset. Data is only
+// appended to it, so slices of it which States have stay valid.
+type streamChunk struct {
+	s     *stream
+	b     []byte
+	start int
+	// line and column of the start
+	line, column int
+}
+
+// fill reads stream until it has data up to the given offset or EOF
+func (st *stream) fill(to int, file *sourceFile) {
+	for !st.eof && st.cur.start+len(st.cur.b) < to {
+		st.compact(file)
+		c := st.cur
+		if cap(c.b)-len(c.b) < streamReadSize {
+			b := make([]byte, len(c.b), 2*len(c.b)+streamReadSize)
+			copy(b, c.b)
+			c.b = b
+		}
+		n, err := st.r.Read(c.b[len(c.b):cap(c.b)])
+		c.b = c.b[:len(c.b)+n]
+		if err != nil {
+			st.eof = true
+			if err != io.EOF {
+				st.err = err
+			}
+		}
+	}
+}
+
+// compact starts new chunk from `keep` offset when data before it is big
+// enough
+func (st *stream) compact(file *sourceFile) {
+	c := st.cur
+	drop := st.keep - c.start
+	if drop < streamReadSize || drop < len(c.b)/2 {
+		return
+	}
+	tab := 1
+	if file != nil {
+		tab = file.tabWidth
+	}
+	line, col := countLineColumn(c.b[:drop], c.line, c.column, tab)
+	b := make([]byte, len(c.b)-drop, len(c.b)-drop+streamReadSize)
+	copy(b, c.b[drop:])
+	st.cur = &streamChunk{s: st, b: b, start: st.keep, line: line, column: col}
+}
+
+// part returns the chunk of stream having data at the State position. It's
+// the current chunk if it has, so the State can read ahead.
+func (s *State) part() *streamChunk {
+	c := s.chunk
+	if cur := c.s.cur; s.at >= cur.start {
+		c = cur
+	}
+	return c
+}
+
+// buf returns data which the State refers and offset of its start
+func (s *State) buf() (b []byte, start int) {
+	if s.chunk == nil {
+		return s.source, 0
+	}
+	c := s.part()
+	return c.b, c.start
+}
+
+// release lets stream to discard data before the State except few bytes to
+// show in errors
+func (s *State) release() {
+	if s.chunk == nil {
+		return
+	}
+	st := s.chunk.s
+	if keep := s.at - streamKeepBefore; keep > st.keep {
+		st.keep = keep
+	}
+}
+
+// readErr returns error of reading stream if any
+func (s *State) readErr() error {
+	if s.chunk == nil {
+		return nil
+	}
+	return s.chunk.s.err
+}
+
+// bytesToIfKept returns bytes up to the given State like BytesTo does, or nil
+// if they were discarded from stream
+func (s *State) bytesToIfKept(to *State) []byte {
+	if s.need(to.at) < to.at {
+		return nil
+	}
+	return s.BytesTo(to)
+}
@@ -0,0 +1,113 @@
+package lr0
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+func TestNewReaderState(t *testing.T) {
+	st := NewReaderState("x", iotest.OneByteReader(strings.NewReader("foo bar\nбаз")))
+	next, b := st.TakeBytesFunc(byteIsNotSpace)
+	if string(b) != "foo" || next.Offset() != 3 {
+		t.Errorf("foo: %q at %d", b, next.Offset())
+	}
+	next, r := next.FF(5).TakeRunes(2)
+	if string(r) != "ба" || next.Offset() != 12 {
+		t.Errorf("runes: %q at %d", string(r), next.Offset())
+	}
+	if p := next.Position().String(); p != "x:2:3" {
+		t.Errorf("position %s", p)
+	}
+	if s := st.BytesTo(next); string(s) != "foo bar\nба" {
+		t.Errorf("bytes %q", s)
+	}
+	if next.IsEOF() || !next.FF(2).IsEOF() || st.RestLen() != 14 {
+		t.Error("EOF")
+	}
+	if !bytes.Equal(next.RestBytes(), []byte("з")) {
+		t.Errorf("rest %q", next.RestBytes())
+	}
+}
+
+func TestParser_Stream(t *testing.T) {
+	p := New(
+		[]Terminal{
+			NewTerm(tInt, "int").FuncByte(isDigit, bytesToInt),
+			NewTerm(tPlus, `"+"`).Hide().Str("+"),
+			NewWhitespace().FuncRune(unicode.IsSpace),
+		},
+		[]NonTerminalDefinition{
+			NewNT(nGoal, "Goal").Main().Is(nSum),
+			NewNT(nSum, "Sum").
+				Is(nSum, tPlus, tInt).Do(calc2IntSum).
+				Is(tInt),
+		},
+	)
+	const count = 100000
+	input := "1" + strings.Repeat(" +\n1", count)
+
+	t.Run("ok", func(t *testing.T) {
+		st := NewReaderState("", strings.NewReader(input))
+		v, err := p.Parse(st)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v != count+1 {
+			t.Errorf("result %v", v)
+		}
+		if c := cap(st.chunk.s.cur.b); c > 4*streamReadSize {
+			t.Errorf("buffer is %d bytes", c)
+		}
+	})
+
+	t.Run("stale", func(t *testing.T) {
+		st := NewReaderState("", strings.NewReader(input))
+		var ends []*State
+		err := p.ParseEach(st, func(_ any, end *State) error {
+			ends = append(ends, end)
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ends) != 1 || ends[0].Offset() != len(input) || !ends[0].IsEOF() {
+			t.Fatalf("ends %v", ends)
+		}
+		// the start is far behind, but it doesn't panic
+		if b := st.RestBytes(); len(b) == 0 || len(b) >= len(input) || !strings.HasPrefix(input, string(b)) {
+			t.Errorf("rest %d bytes", len(b))
+		}
+		if s := st.String(); s != "⏵⟪1␠+␊1␠+␊1␠+␊1␠+␊1␠+␊1␠+␊1␠+␊1␠⟫" {
+			t.Errorf("string %s", s)
+		}
+		if p := ends[0].Position().String(); p != "100001:2" {
+			t.Errorf("position %s", p)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		_, err := p.Parse(NewReaderState("in.txt", strings.NewReader(input+"++")))
+		if !errors.Is(err, ErrParse) {
+			t.Fatal("another error", err)
+		}
+		const expect = "unexpected input: expected int: parse error at in.txt:100001:3 near ⟪1␠+␊1␠+␊1␠+␊1␠+␊1␠+␊1␠+␊1␠+␊1+⟫⏵⟪+⟫"
+		if err.Error() != expect {
+			t.Errorf("error: %s", err)
+		}
+	})
+
+	t.Run("read error", func(t *testing.T) {
+		errRead := errors.New("read failed")
+		r := io.MultiReader(strings.NewReader(input), iotest.ErrReader(errRead))
+		_, err := p.Parse(NewReaderState("", r))
+		if !errors.Is(err, errRead) {
+			t.Fatal("another error", err)
+		}
+	})
+}
@@ -3,7 +3,9 @@ package lr0
 import (
 	"fmt"
 	"io"
+	"sort"
 	"strings"
+	"sync"
 	"unicode/utf8"
 
 	"github.com/pkg/errors"
@@ -13,38 +15,130 @@ const stateFormatContext = 30
 
 // NewState creates new State for the given buffer `input` pointing to its start
 func NewState(input []byte) *State {
+	return NewNamedState("", input)
+}
+
+// NewNamedState creates new State like NewState does for the buffer with the
+// given name, like a file name. The name is shown in positions and errors:
+//
+//	file.txt:3:14
+//
+// See also FileSet.
+func NewNamedState(name string, input []byte) *State {
 	return &State{
 		source: input,
+		file:   &sourceFile{name: name, tabWidth: 1},
 	}
 }
 
 // State describes an immutable state of reading the underlying buffer at the
 // given position
 type State struct {
+	// source is the buffer, nil for stream
 	source []byte
-	at     int
+	// at is offset from the buffer start
+	at int
+	// file is shared by all States of the same buffer
+	file *sourceFile
+	// chunk is set for State of stream, see NewReaderState
+	chunk *streamChunk
+}
+
+// sourceFile describes a buffer. It keeps offsets of lines starts which are
+// indexed once on demand.
+type sourceFile struct {
+	name string
+	// base is Pos of the buffer start in FileSet, or 0
+	base     Pos
+	tabWidth int
+	once     sync.Once
+	starts   []int
+}
+
+func (l *sourceFile) getBase() Pos {
+	if l == nil {
+		return 0
+	}
+	return l.base
+}
+
+func (l *sourceFile) lines(source []byte) []int {
+	l.once.Do(func() {
+		l.starts = []int{0}
+		for i, b := range source {
+			if b == '\n' {
+				l.starts = append(l.starts, i+1)
+			}
+		}
+	})
+	return l.starts
+}
+
+// WithTabWidth returns new State for the same buffer at the same position,
+// which counts columns for tab character up to the next multiple of `width`
+// plus one. By default, tab is one column like any other character.
+//
+//	NewState(input).WithTabWidth(4)
+func (s *State) WithTabWidth(width int) *State {
+	if width < 1 {
+		width = 1
+	}
+	return &State{
+		source: s.source,
+		at:     s.at,
+		file:   &sourceFile{name: s.Name(), base: s.file.getBase(), tabWidth: width},
+		chunk:  s.chunk,
+	}
+}
+
+// Name returns name of the buffer given to NewNamedState or FileSet.AddFile
+func (s *State) Name() string {
+	if s.file == nil {
+		return ""
+	}
+	return s.file.name
+}
+
+// Pos returns position of the State in FileSet, or the offset plus one for
+// State not from FileSet. Zero is NoPos.
+func (s *State) Pos() Pos {
+	return s.file.getBase() + Pos(s.at) + 1
+}
+
+// Position returns full description of the current position
+func (s *State) Position() Position {
+	line, col := s.LineColumn()
+	return Position{Filename: s.Name(), Offset: s.at, Line: line, Column: col}
 }
 
 // to returns new State for the same buffer pointing to the given position `pos`
 func (s *State) to(pos int) *State {
-	pos = fixOffset(pos, len(s.source))
+	if pos < 0 {
+		panic(ErrNegativeOffset)
+	}
+	pos = s.need(pos)
 	if pos == s.at {
 		return s
 	}
 	return &State{
 		source: s.source,
 		at:     pos,
+		file:   s.file,
+		chunk:  s.chunk,
 	}
 }
 
 // IsEOF checks if the position is at EOF
 func (s *State) IsEOF() bool {
-	return s.at >= len(s.source)
+	return s.need(s.at+1) <= s.at
 }
 
 // Len returns length of the underlying buffer
+//
+// For State from NewReaderState it's length of data read so far.
 func (s *State) Len() int {
-	return len(s.source)
+	b, start := s.buf()
+	return start + len(b)
 }
 
 // Offset returns the current offset
@@ -52,14 +146,59 @@ func (s *State) Offset() int {
 	return s.at
 }
 
+// LineColumn returns 1-based line and column of the current position. Column
+// counts runes, see also WithTabWidth.
+//
+// Lines starts are indexed once for the whole buffer, so only the current
+// line is scanned. State from NewReaderState scans its part of stream.
+func (s *State) LineColumn() (line, column int) {
+	file := s.file
+	if file == nil {
+		file = &sourceFile{tabWidth: 1}
+	}
+	if s.chunk != nil {
+		c := s.part()
+		return countLineColumn(c.b[:s.at-c.start], c.line, c.column, file.tabWidth)
+	}
+	starts := file.lines(s.source)
+	line = sort.Search(len(starts), func(i int) bool { return starts[i] > s.at })
+	_, column = countLineColumn(s.source[starts[line-1]:s.at], line, 1, file.tabWidth)
+	return
+}
+
+// countLineColumn returns line and column after the given bytes which start
+// at the given line and column
+func countLineColumn(b []byte, line, column, tab int) (int, int) {
+	for i := 0; i < len(b); {
+		switch {
+		case b[i] == '\n':
+			line++
+			column = 1
+			i++
+		case b[i] == '\t' && tab > 1:
+			column = (column-1)/tab*tab + tab + 1
+			i++
+		default:
+			_, n := utf8.DecodeRune(b[i:])
+			column++
+			i += n
+		}
+	}
+	return line, column
+}
+
 // RestLen returns the rest unread length of the underlying buffer
+//
+// State from NewReaderState reads the whole stream to know it.
 func (s *State) RestLen() int {
-	return len(s.source) - s.at
+	return s.need(maxOffset) - s.at
 }
 
 // RestBytes returns slice of rest bytes from the current position
+//
+// State from NewReaderState reads the whole stream to return it.
 func (s *State) RestBytes() []byte {
-	return s.source[s.at:]
+	return s.slice(s.at, s.need(maxOffset))
 }
 
 // BytesTo returns slice of underlying buffer from current position to the given
@@ -71,11 +210,14 @@ func (s *State) BytesTo(to *State) []byte {
 // BytesToOffset returns slice of underlying buffer from current position to the
 // given position
 func (s *State) BytesToOffset(offset int) []byte {
-	to := fixOffset(offset, len(s.source))
+	if offset < 0 {
+		panic(ErrNegativeOffset)
+	}
+	to := s.need(offset)
 	if to < s.at {
 		panic(errors.Wrapf(ErrNegativeOffset, "from offset %v to backward offset %v", s.at, to))
 	}
-	return s.source[s.at:to]
+	return s.slice(s.at, to)
 }
 
 // Byte returns a byte from current position
@@ -85,7 +227,7 @@ func (s *State) Byte() byte {
 	if s.IsEOF() {
 		panic(io.EOF)
 	}
-	return s.source[s.at]
+	return s.slice(s.at, s.at+1)[0]
 }
 
 // Rune returns a rune from the current position
@@ -132,7 +274,7 @@ func (s *State) TakeByteFunc(valid func(byte) bool) (*State, byte) {
 // truncated by EOF
 func (s *State) TakeBytes(n int) (*State, []byte) {
 	next := s.FF(n)
-	return next, s.source[s.at:next.at]
+	return next, s.slice(s.at, next.at)
 }
 
 // TakeBytesFunc return next State and slice of bytes which are valid by the
@@ -150,7 +292,7 @@ func (s *State) TakeBytesFunc(valid func(byte) bool) (*State, []byte) {
 	if next.at == s.at {
 		return s, nil
 	}
-	return next, s.source[s.at:next.at]
+	return next, s.slice(s.at, next.at)
 }
 
 // TakeRune returns a rune from the current position and new State with next
@@ -232,41 +374,71 @@ func (s *State) cutUpTo(n int) []byte {
 	return s.BytesToOffset(s.at + n)
 }
 
-func fixOffset(offset, length int) int {
-	if offset < 0 {
-		panic(ErrNegativeOffset)
+// need returns the given offset if the buffer has data up to it, or the
+// buffer end otherwise. State from NewReaderState reads stream up to it, and
+// the end is where data kept for the State ends, see NewReaderState.
+func (s *State) need(offset int) int {
+	b, start := s.buf()
+	end := start + len(b)
+	if offset <= end {
+		return offset
+	}
+	if s.chunk == nil {
+		return end
+	}
+	st := s.chunk.s
+	st.fill(offset, s.file)
+	b, start = s.buf()
+	end = start + len(b)
+	// the current chunk continues the State's one
+	if cur := st.cur; start != cur.start && cur.start <= end {
+		end = cur.start + len(cur.b)
 	}
-	if offset > length {
-		return length
+	if offset > end {
+		return end
 	}
 	return offset
 }
 
+// slice returns bytes from buffer between offsets
+func (s *State) slice(from, to int) []byte {
+	to = s.need(to)
+	b, start := s.buf()
+	if to-start <= len(b) {
+		return b[from-start : to-start]
+	}
+	// the rest is in the current chunk of stream
+	cur := s.chunk.s.cur
+	out := make([]byte, 0, to-from)
+	out = append(out, b[from-start:]...)
+	return append(out, cur.b[start+len(b)-cur.start:to-cur.start]...)
+}
+
 func (s *State) getBefore() string {
-	from := s.at
+	b, start := s.buf()
+	from := s.at - start
 	rest := stateFormatContext
 	for from > 0 && rest > 0 {
 		from--
-		if utf8.RuneStart(s.source[from]) {
+		if utf8.RuneStart(b[from]) {
 			rest--
 		}
 	}
-	return string(s.source[from:s.at])
+	return string(b[from : s.at-start])
 }
 func (s *State) getAfter() string {
-	to := s.at
-	L := len(s.source)
+	b := s.slice(s.at, s.at+stateFormatContext*utf8.UTFMax+1)
+	to := 0
 	started := 0
-	for to < L {
-		if utf8.RuneStart(s.source[to]) {
+	for ; to < len(b); to++ {
+		if utf8.RuneStart(b[to]) {
 			started++
 			if started > stateFormatContext {
 				break
 			}
 		}
-		to++
 	}
-	return string(s.BytesToOffset(to))
+	return string(b[:to])
 }
 
 func (s *State) String() string {
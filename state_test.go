@@ -79,6 +79,43 @@ func TestState_Len(t *testing.T) {
 	}
 }
 
+func TestState_LineColumn(t *testing.T) {
+	a := NewState([]byte("ab\nцd\n\nx"))
+	for offset, expect := range map[int][2]int{
+		0: {1, 1},
+		2: {1, 3},
+		3: {2, 1},
+		5: {2, 2},
+		6: {2, 3},
+		7: {3, 1},
+		8: {4, 1},
+		9: {4, 2},
+	} {
+		if line, col := a.to(offset).LineColumn(); line != expect[0] || col != expect[1] {
+			t.Errorf("offset %d: %d:%d", offset, line, col)
+		}
+	}
+}
+
+func TestState_WithTabWidth(t *testing.T) {
+	a := NewState([]byte("\tab\n x\ty\tz")).WithTabWidth(4)
+	for offset, expect := range map[int][2]int{
+		1:  {1, 5},
+		2:  {1, 6},
+		5:  {2, 2},
+		7:  {2, 5},
+		9:  {2, 9},
+		10: {2, 10},
+	} {
+		if line, col := a.to(offset).LineColumn(); line != expect[0] || col != expect[1] {
+			t.Errorf("offset %d: %d:%d", offset, line, col)
+		}
+	}
+	if line, col := NewState([]byte("\tx")).to(1).LineColumn(); line != 1 || col != 2 {
+		t.Errorf("default tab width: %d:%d", line, col)
+	}
+}
+
 func TestState_Offset(t *testing.T) {
 	a := NewState(testStateSource)
 	if a.Offset() != 0 {
@@ -0,0 +1,294 @@
+package lr0
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// ErrTableData means that a TableData cannot be used for the grammar
+var ErrTableData = errors.Wrap(ErrDefine, "bad table data")
+
+// TableData is a built parsing table in plain form to serialize it with
+// encoding/json or encoding/gob, so the table can be built once and loaded
+// later with WithTable.
+//
+// Rules are referred by index in the grammar, which is definition order.
+type TableData struct {
+	// Fingerprint identifies the grammar and options the table was built for
+	Fingerprint string
+	// Mode is the mode the table was built in
+	Mode TableMode
+	// Cores is count of different LR(0) cores of states, see TableStats
+	Cores int
+	Rows  []RowData
+}
+
+// RowData is a single row of TableData. It is used by the loaded table as is,
+// so lists must be sorted by Id.
+type RowData struct {
+	AcceptEof bool `json:",omitempty"`
+	// Shift binds terminals to next state
+	Shift []Action `json:",omitempty"`
+	// Goto binds non-terminals to next state
+	Goto []Action `json:",omitempty"`
+	// Reduce is index of a rule to reduce regardless of lookahead, or -1
+	Reduce int
+	// Lookahead binds terminals and EOF to index of a rule to reduce
+	Lookahead []Action `json:",omitempty"`
+	// Denied terminals are error due to non-associativity
+	Denied []Id `json:",omitempty"`
+}
+
+// Action is an action for a symbol in RowData: next state index for Shift
+// and Goto, or rule index for Lookahead
+type Action struct {
+	Id Id
+	To int
+}
+
+// WithTable lets to use previously built table instead of building it again.
+// Terminals, rules and other options must be the same as the table was built
+// with, and rows must be consistent with the grammar, otherwise New panics and
+// NewE returns ErrTableData.
+//
+//	data := New(terminals, rules, WithMode(LALR1)).Table().Data()
+//	b, err := json.Marshal(data)
+//	...
+//	var data TableData
+//	err := json.Unmarshal(b, &data)
+//	...
+//	parser := New(terminals, rules, WithMode(LALR1), WithTable(&data))
+//
+// Table.Items returns nil for the loaded table.
+func WithTable(data *TableData) Option {
+	return func(c *config) {
+		c.table = data
+	}
+}
+
+// grammarFingerprint returns a hash of everything what affects the table:
+// symbols, rules and options
+func grammarFingerprint(g *grammar, c *config) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "mode %d\n", c.mode)
+	for _, id := range g.GetTerminalIdsSet().Ids() {
+		fmt.Fprintf(h, "terminal %d %q %v\n", id, g.SymbolName(id), g.IsHidden(id))
+	}
+	for _, r := range g.rules {
+		fmt.Fprintf(h, "rule %d %q %v", r.Subject(), g.SymbolName(r.Subject()), r.HasEOF())
+		for i, id := range r.Definition() {
+			fmt.Fprintf(h, " %d/%v", id, r.IsHidden(i))
+		}
+		if pr, ok := r.(precedenceRule); ok {
+			fmt.Fprintf(h, " prec %d", pr.Precedence())
+		}
+		fmt.Fprintln(h)
+	}
+	ids := make([]Id, 0, len(c.precedence))
+	for id := range c.precedence {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	for _, id := range ids {
+		p := c.precedence[id]
+		fmt.Fprintf(h, "precedence %d %d %d\n", id, p.level, p.assoc)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (t *table) Data() *TableData {
+	data := &TableData{
+		Fingerprint: t.fingerprint,
+		Mode:        t.stats.Mode,
+		Cores:       t.stats.Cores,
+		Rows:        make([]RowData, 0, len(t.rows)),
+	}
+	for _, row := range t.rows {
+		rd := row.data
+		rd.Shift = cloneActions(rd.Shift)
+		rd.Goto = cloneActions(rd.Goto)
+		rd.Lookahead = cloneActions(rd.Lookahead)
+		if len(rd.Denied) != 0 {
+			rd.Denied = append([]Id(nil), rd.Denied...)
+		} else {
+			rd.Denied = nil
+		}
+		data.Rows = append(data.Rows, rd)
+	}
+	return data
+}
+
+func cloneActions(list []Action) []Action {
+	if len(list) == 0 {
+		return nil
+	}
+	return append([]Action(nil), list...)
+}
+
+// loadTable creates table from the given data checking it matches the grammar.
+// Rows use the data as is without copying.
+func loadTable(g *grammar, c *config, data *TableData) (*table, error) {
+	fp := grammarFingerprint(g, c)
+	if data.Fingerprint != fp {
+		return nil, errors.Wrap(ErrTableData, "fingerprint differs, the table was built for another grammar or options")
+	}
+	// first rows are initial states of main rules
+	if len(data.Rows) < len(g.mainIndices) {
+		return nil, errors.Wrap(ErrTableData, "no rows for initial states")
+	}
+
+	var (
+		rows     = make([]*tableRow, 0, len(data.Rows))
+		badState = func(si int) bool { return si < 0 || si >= len(data.Rows) }
+		badRule  = func(ri int) bool { return ri < 0 || ri >= len(g.rules) }
+		// checkActions checks actions are sorted by Id and valid
+		checkActions = func(i int, name string, list []Action, bad func(a Action) bool) error {
+			for j, a := range list {
+				if j > 0 && list[j-1].Id >= a.Id {
+					return errors.Wrapf(ErrTableData, "row %d: %s is not sorted at %d", i, name, a.Id)
+				}
+				if bad(a) {
+					return errors.Wrapf(ErrTableData, "row %d: bad %s %d -> %d", i, name, a.Id, a.To)
+				}
+			}
+			return nil
+		}
+	)
+	for i := range data.Rows {
+		rd := &data.Rows[i]
+		if err := checkActions(i, "shift", rd.Shift, func(a Action) bool {
+			return badState(a.To) || !g.IsTerminal(a.Id)
+		}); err != nil {
+			return nil, err
+		}
+		if err := checkActions(i, "goto", rd.Goto, func(a Action) bool {
+			_, ok := g.subjectsIndices[a.Id]
+			return badState(a.To) || !ok
+		}); err != nil {
+			return nil, err
+		}
+		if rd.Reduce != -1 && badRule(rd.Reduce) {
+			return nil, errors.Wrapf(ErrTableData, "row %d: bad reduce rule %d", i, rd.Reduce)
+		}
+		if err := checkActions(i, "lookahead", rd.Lookahead, func(a Action) bool {
+			return badRule(a.To) || (a.Id != tEof && !g.IsTerminal(a.Id))
+		}); err != nil {
+			return nil, err
+		}
+		for j, id := range rd.Denied {
+			if j > 0 && rd.Denied[j-1] >= id {
+				return nil, errors.Wrapf(ErrTableData, "row %d: denied is not sorted at %d", i, id)
+			}
+			if !g.IsTerminal(id) {
+				return nil, errors.Wrapf(ErrTableData, "row %d: denied %d is not a terminal", i, id)
+			}
+		}
+		rows = append(rows, &tableRow{data: *rd, rules: g.rules})
+	}
+	if err := checkTableRows(g, rows); err != nil {
+		return nil, err
+	}
+
+	return &table{
+		rows:        rows,
+		stats:       TableStats{Mode: data.Mode, States: len(rows), Cores: data.Cores},
+		g:           g,
+		fingerprint: fp,
+	}, nil
+}
+
+// checkTableRows checks that reduce rules and accepted EOF of rows match the
+// paths the rows are reached by, and reduced subjects have goto after reduce
+func checkTableRows(g *grammar, rows []*tableRow) error {
+	// preds are incoming actions of every row, where Action.To is the source
+	preds := make([][]Action, len(rows))
+	for si, row := range rows {
+		for _, actions := range [][]Action{row.data.Shift, row.data.Goto} {
+			for _, a := range actions {
+				preds[a.To] = append(preds[a.To], Action{Id: a.Id, To: si})
+			}
+		}
+	}
+	isMain := make(map[int]bool, len(g.mainIndices))
+	for _, ri := range g.mainIndices {
+		isMain[ri] = true
+	}
+
+	for si, row := range rows {
+		reduces := make(map[int]bool)
+		if row.data.Reduce != -1 {
+			reduces[row.data.Reduce] = true
+		}
+		for _, a := range row.data.Lookahead {
+			reduces[a.To] = true
+		}
+		for ri := range reduces {
+			r := g.rules[ri]
+			if isMain[ri] {
+				// LR(0) row has the main rule when it accepts EOF, which is
+				// checked below
+				if !row.data.AcceptEof {
+					return errors.Wrapf(ErrTableData, "row %d: main rule %d cannot be reduced", si, ri)
+				}
+				continue
+			}
+			bases, ok := reduceBases(si, r.Definition(), preds)
+			if !ok {
+				return errors.Wrapf(ErrTableData, "row %d: reduce rule %d does not match the state", si, ri)
+			}
+			for _, base := range bases {
+				if _, ok := rows[base].GotoAction(r.Subject()); !ok {
+					return errors.Wrapf(ErrTableData, "row %d: no goto %d after reduce rule %d in row %d", base, r.Subject(), ri, si)
+				}
+			}
+		}
+
+		if row.data.AcceptEof {
+			accepted := false
+			for start, ri := range g.mainIndices {
+				bases, ok := reduceBases(si, g.rules[ri].Definition(), preds)
+				if ok && len(bases) == 1 && bases[0] == start {
+					accepted = true
+					break
+				}
+			}
+			if !accepted {
+				return errors.Wrapf(ErrTableData, "row %d: accepted EOF does not match the state", si)
+			}
+		}
+	}
+	return nil
+}
+
+// reduceBases returns rows from which the given row is reached by the given
+// definition, so goto by its subject happens in them after reduce. It returns
+// false when any path to the row does not match the definition.
+func reduceBases(si tableStateIndex, def []Id, preds [][]Action) ([]tableStateIndex, bool) {
+	cur := []tableStateIndex{si}
+	for k := len(def) - 1; k >= 0; k-- {
+		var (
+			next []tableStateIndex
+			seen = make(map[tableStateIndex]bool)
+		)
+		for _, s := range cur {
+			if len(preds[s]) == 0 {
+				return nil, false
+			}
+			for _, p := range preds[s] {
+				if p.Id != def[k] {
+					return nil, false
+				}
+				if !seen[p.To] {
+					seen[p.To] = true
+					next = append(next, p.To)
+				}
+			}
+		}
+		cur = next
+	}
+	return cur, true
+}
@@ -0,0 +1,185 @@
+package lr0
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/pkg/errors"
+	"github.com/vovan-ve/go-lr0-parser/internal/testutils"
+)
+
+func TestTableData(t *testing.T) {
+	terminals := []Terminal{
+		NewTerm(tInt, "int").FuncByte(isDigit, bytesToInt),
+		NewTerm(tPlus, `"+"`).Hide().Str("+"),
+		NewTerm(tMinus, `"-"`).Hide().Str("-"),
+		NewTerm(tMul, `"*"`).Hide().Str("*"),
+		NewTerm(tLess, `"<"`).Hide().Str("<"),
+		NewWhitespace().FuncRune(unicode.IsSpace),
+	}
+	rules := []NonTerminalDefinition{
+		NewNT(nGoal, "Goal").Main().Is(nSum),
+		NewNT(nSum, "E").
+			Is(nSum, tPlus, nSum).Do(calc2IntSum).
+			Is(nSum, tMinus, nSum).Do(calc2IntSub).
+			Is(nSum, tMul, nSum).Do(func(a, b int) int { return a * b }).
+			Is(nSum, tLess, nSum).Do(func(a, b int) int { return b - a }).
+			Is(tMinus, nSum).Prec(tUMinus).Do(func(a int) int { return -a }).
+			Is(tInt),
+	}
+	opts := []Option{
+		WithMode(LALR1),
+		NonAssoc(tLess),
+		Left(tPlus, tMinus),
+		Left(tMul),
+		Right(tUMinus),
+	}
+	orig := New(terminals, rules, opts...)
+	data := orig.Table().Data()
+
+	expectSame := func(t *testing.T, data *TableData) {
+		t.Helper()
+		p, err := NewE(terminals, rules, append(opts, WithTable(data))...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		g := p.(*parser).g
+		if got, want := p.(*parser).t.dump(g), orig.(*parser).t.dump(g); got != want {
+			t.Errorf("loaded table:\n%s\nexpected:\n%s", got, want)
+		}
+		if p.Stats() != orig.Stats() {
+			t.Errorf("stats %+v", p.Stats())
+		}
+		if p.Table().Items(0) != nil {
+			t.Error("items are available")
+		}
+		v, err := p.Parse(NewState([]byte("2 * -3 - 4 - 5")))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v != -15 {
+			t.Errorf("result %v", v)
+		}
+		if _, err = p.Parse(NewState([]byte("1 < 2 < 3"))); err == nil {
+			t.Error("non-associative is ok")
+		}
+	}
+
+	t.Run("json", func(t *testing.T) {
+		b, err := json.Marshal(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var loaded TableData
+		if err = json.Unmarshal(b, &loaded); err != nil {
+			t.Fatal(err)
+		}
+		expectSame(t, &loaded)
+	})
+
+	t.Run("gob", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		if err := gob.NewEncoder(buf).Encode(data); err != nil {
+			t.Fatal(err)
+		}
+		var loaded TableData
+		if err := gob.NewDecoder(buf).Decode(&loaded); err != nil {
+			t.Fatal(err)
+		}
+		expectSame(t, &loaded)
+	})
+
+	t.Run("another options", func(t *testing.T) {
+		_, err := NewE(terminals, rules, append(opts, WithMode(SLR1), WithTable(data))...)
+		if !errors.Is(err, ErrTableData) {
+			t.Fatal("unexpected error:", err)
+		}
+	})
+
+	t.Run("another grammar", func(t *testing.T) {
+		defer testutils.ExpectPanicError(t, ErrTableData)
+		New(terminals, []NonTerminalDefinition{
+			NewNT(nGoal, "Goal").Main().Is(nSum),
+			NewNT(nSum, "E").
+				Is(nSum, tPlus, nSum).Do(calc2IntSum).
+				Is(nSum, tMinus, nSum).Do(calc2IntSub).
+				Is(nSum, tMul, nSum).Do(func(a, b int) int { return a * b }).
+				Is(nSum, tLess, nSum).Do(func(a, b int) int { return b - a }).
+				Is(tMinus, nSum).Prec(tUMinus).Do(func(a int) int { return -a }).
+				Is(tInt).
+				Is(tPlus, nSum).Prec(tUMinus),
+		}, append(opts, WithTable(data))...)
+	})
+
+	t.Run("bad data", func(t *testing.T) {
+		bad := *data
+		bad.Rows = append([]RowData{{Reduce: len(rules) + 100}}, data.Rows[1:]...)
+		_, err := NewE(terminals, rules, append(opts, WithTable(&bad))...)
+		if !errors.Is(err, ErrTableData) {
+			t.Fatal("unexpected error:", err)
+		}
+	})
+
+	t.Run("unsorted", func(t *testing.T) {
+		bad := *data
+		row := data.Rows[0]
+		if len(row.Shift) < 2 {
+			t.Fatal("need more shifts", row.Shift)
+		}
+		row.Shift = append([]Action{row.Shift[len(row.Shift)-1]}, row.Shift[:len(row.Shift)-1]...)
+		bad.Rows = append([]RowData{row}, data.Rows[1:]...)
+		_, err := NewE(terminals, rules, append(opts, WithTable(&bad))...)
+		if !errors.Is(err, ErrTableData) {
+			t.Fatal("unexpected error:", err)
+		}
+	})
+
+	t.Run("inconsistent", func(t *testing.T) {
+		// rule indices are in definition order
+		const ruleSum, ruleInt = 1, 6
+		var reduceInt int
+		for i, rd := range data.Rows {
+			if rd.Reduce == ruleInt {
+				reduceInt = i
+			}
+		}
+		if reduceInt == 0 {
+			t.Fatal("no reduce by int")
+		}
+		for name, c := range map[string]struct {
+			edit   func(rows []RowData)
+			expect string
+		}{
+			"no goto": {
+				edit:   func(rows []RowData) { rows[0].Goto = nil },
+				expect: "no goto",
+			},
+			"denied non-terminal": {
+				edit:   func(rows []RowData) { rows[reduceInt].Denied = []Id{nSum} },
+				expect: "is not a terminal",
+			},
+			"reduce another rule": {
+				edit: func(rows []RowData) {
+					rows[reduceInt].Reduce = ruleSum
+					for i := range rows[reduceInt].Lookahead {
+						rows[reduceInt].Lookahead[i].To = ruleSum
+					}
+				},
+				expect: "does not match the state",
+			},
+		} {
+			t.Run(name, func(t *testing.T) {
+				bad := orig.Table().Data()
+				c.edit(bad.Rows)
+				_, err := NewE(terminals, rules, append(opts, WithTable(bad))...)
+				if !errors.Is(err, ErrTableData) || !strings.Contains(err.Error(), c.expect) {
+					t.Fatal("unexpected error:", err)
+				}
+			})
+		}
+	})
+}
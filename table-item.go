@@ -60,3 +60,23 @@ func (i tableItem) Shift() tableItem {
 	next.nextIndex++
 	return next
 }
+
+// dump renders the item with `>` pointing to the current position
+//
+//	Sum : Sum > "+" Product
+func (i tableItem) dump(reg SymbolRegistry) string {
+	s := dumpId(i.Subject(), reg) + " :"
+	for n, id := range i.Definition() {
+		if n == i.nextIndex {
+			s += " >"
+		}
+		s += " " + dumpId(id, reg)
+	}
+	if !i.HasFurther() {
+		s += " >"
+	}
+	if i.HasEOF() {
+		s += " $"
+	}
+	return s
+}
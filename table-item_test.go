@@ -82,3 +82,19 @@ func TestTableItem_Navigate(t *testing.T) {
 		i3.Shift()
 	})
 }
+
+func TestTableItem_dump(t *testing.T) {
+	main := newTableItem(testTableItemsetRuleGoal)
+	for expected, it := range map[string]tableItem{
+		`Goal : > Sum $`:      main,
+		`Goal : Sum > $`:      main.Shift(),
+		`Sum : > Sum "+" Val`: newTableItem(testTableItemsetRuleSumPlus),
+		`Sum : Sum "+" > Val`: newTableItem(testTableItemsetRuleSumPlus).Shift().Shift(),
+		`Sum : Sum "+" Val >`: newTableItem(testTableItemsetRuleSumPlus).Shift().Shift().Shift(),
+		`Val : > zero`:        newTableItem(testTableItemsetRuleValZero),
+	} {
+		if s := it.dump(testTableItemsetGrammar); s != expected {
+			t.Errorf("%q is %q", expected, s)
+		}
+	}
+}
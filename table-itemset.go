@@ -1,17 +1,17 @@
 package lr0
 
-import (
-	"github.com/pkg/errors"
-)
-
 func newTableItemset(items []tableItem, g *grammar) tableItemset {
 	allItems := expandAllPossibleTableItems(items, g)
-	validateTableItemsetDeterministic(allItems, g)
 	return tableItemset{items: allItems}
 }
 
 // expandAllPossibleTableItems returns possible items by expansion all the given items
 // by Grammar
+//
+// An item of empty rule `A : > ε` is final right away, so it will be reduced
+// without shifting anything, and a nullable non-terminal after the position
+// will be passed by the following goto. Lookaheads through nullable
+// non-terminals are handled by grammarSets.
 func expandAllPossibleTableItems(items []tableItem, g *grammar) []tableItem {
 	var final []tableItem
 	knownNext := newIdSet()
@@ -52,47 +52,55 @@ func expandAllPossibleTableItems(items []tableItem, g *grammar) []tableItem {
 	return final
 }
 
-// validateTableItemsetDeterministic checks for bad state in this tableItemset.
-// A problem is reported by panic since it's grammar definition problem
+// validateTableItemsetDeterministic checks for bad state in this tableItemset
+// of LR(0) table with the given terminals to shift. Returns found conflicts:
 //
 // - ErrConflictReduceReduce
 //
 // - ErrConflictShiftReduce
 //
 // https://en.wikipedia.org/wiki/LR_parser#Conflicts_in_the_constructed_tables
-func validateTableItemsetDeterministic(items []tableItem, g *grammar) {
-	var finite, terminals, nonTerminals []tableItem
+func validateTableItemsetDeterministic(items []tableItem, shifts readonlyIdSet, g *grammar) []*conflict {
+	var (
+		finite []Rule
+		found  []*conflict
+	)
 	for _, it := range items {
 		if !it.HasFurther() {
-			finite = append(finite, it)
-			continue
+			finite = append(finite, it.Rule)
 		}
-		if g.IsTerminal(it.Expected()) {
-			terminals = append(terminals, it)
-			continue
-		}
-		nonTerminals = append(nonTerminals, it)
 	}
 
 	// Check for Shift-Reduce conflicts
-	if len(finite) != 0 && len(terminals) != 0 {
+	if len(finite) != 0 && shifts.Count() != 0 {
 		restTerminals := g.GetTerminalIdsSet()
-		for _, it := range terminals {
-			restTerminals.Remove(it.Expected())
+		for _, id := range shifts.Ids() {
+			restTerminals.Remove(id)
 		}
 		if restTerminals.Count() == 0 {
-			panic(errors.WithStack(ErrConflictShiftReduce))
+			sr := newConflict(ErrConflictShiftReduce)
+			set := tableItemset{items: items}
+			for _, id := range shifts.Ids() {
+				sr.add(id, finite...)
+				sr.add(id, set.rulesExpecting(id)...)
+			}
+			found = append(found, sr)
 		}
 	}
 
 	// Check for Reduce-Reduce conflicts
 	if len(finite) > 1 {
-		panic(errors.WithStack(ErrConflictReduceReduce))
+		rr := newConflict(ErrConflictReduceReduce)
+		rr.add(InvalidId, finite...)
+		found = append(found, rr)
 	}
+	return found
 }
 
 type tableItemset struct {
 	items []tableItem
+	// lookahead is set for every item in canonical LR(1) itemset only
+	lookahead map[tableItem]idSet
 }
 
 // HasFinalItem checks if this set has tableItem where the next token must be EOF
@@ -105,7 +113,21 @@ func (s tableItemset) HasFinalItem() bool {
 	return false
 }
 
+// rulesExpecting returns rules of items which expect the given Id next, or
+// the main rule of final item when EOF is given
+func (s tableItemset) rulesExpecting(id Id) []Rule {
+	var res []Rule
+	for _, it := range s.items {
+		if it.Expected() == id || (id == tEof && it.HasEOF() && !it.HasFurther()) {
+			res = append(res, it.Rule)
+		}
+	}
+	return res
+}
+
 // ReduceRule returns reduction rule of this set if any, nil otherwise
+//
+// It's for LR(0) table only.
 func (s tableItemset) ReduceRule() Rule {
 	for _, it := range s.items {
 		if !it.HasFurther() {
@@ -117,8 +139,10 @@ func (s tableItemset) ReduceRule() Rule {
 }
 
 // IsEqual checks equality with another tableItemset
+//
+// Lookahead sets are compared too for canonical LR(1) itemsets.
 func (s tableItemset) IsEqual(to tableItemset) bool {
-	if len(s.items) != len(to.items) {
+	if len(s.items) != len(to.items) || len(s.lookahead) != len(to.lookahead) {
 		return false
 	}
 	my := make(map[tableItem]struct{})
@@ -130,15 +154,36 @@ func (s tableItemset) IsEqual(to tableItemset) bool {
 			return false
 		}
 	}
+	for it, la := range s.lookahead {
+		toLa := to.lookahead[it]
+		if la.Count() != toLa.Count() {
+			return false
+		}
+		for id := range la {
+			if !toLa.Has(id) {
+				return false
+			}
+		}
+	}
 	return true
 }
 
 // GetNextItemsets creates next sets for next state by shifting current position
 // in items
+//
+// Shifted items of canonical LR(1) itemset keep their lookahead sets.
 func (s tableItemset) GetNextItemsets(g *grammar) map[Id]tableItemset {
 	ret := make(map[Id]tableItemset)
 	for id, items := range s.getNextSetsMap() {
-		ret[id] = newTableItemset(items, g)
+		if s.lookahead == nil {
+			ret[id] = newTableItemset(items, g)
+			continue
+		}
+		la := make(map[tableItem]idSet, len(items))
+		for _, it := range items {
+			la[it] = s.lookahead[tableItem{Rule: it.Rule, nextIndex: it.nextIndex - 1}]
+		}
+		ret[id] = newTableItemsetLR1(items, la, g)
 	}
 	return ret
 }
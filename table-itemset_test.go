@@ -152,7 +152,7 @@ func TestTableItemset_GetNextItemsets(t *testing.T) {
 		t.Fatal("set 1: no zero")
 	}
 	if !set1zero.IsEqual(
-		tableItemset{[]tableItem{
+		tableItemset{items: []tableItem{
 			newTableItem(testTableItemsetRuleValZero).Shift(),
 		}},
 	) {
@@ -167,7 +167,7 @@ func TestTableItemset_GetNextItemsets(t *testing.T) {
 		t.Fatal("set 1: no one")
 	}
 	if !set1one.IsEqual(
-		tableItemset{[]tableItem{
+		tableItemset{items: []tableItem{
 			newTableItem(testTableItemsetRuleValOne).Shift(),
 		}},
 	) {
@@ -182,7 +182,7 @@ func TestTableItemset_GetNextItemsets(t *testing.T) {
 		t.Fatal("set 1: no val")
 	}
 	if !set1val.IsEqual(
-		tableItemset{[]tableItem{
+		tableItemset{items: []tableItem{
 			newTableItem(testTableItemsetRuleSumVal).Shift(),
 		}},
 	) {
@@ -197,7 +197,7 @@ func TestTableItemset_GetNextItemsets(t *testing.T) {
 		t.Fatal("set 1: no sum")
 	}
 	if !set1sum.IsEqual(
-		tableItemset{[]tableItem{
+		tableItemset{items: []tableItem{
 			newTableItem(testTableItemsetRuleGoal).Shift(),
 			newTableItem(testTableItemsetRuleSumPlus).Shift(),
 			newTableItem(testTableItemsetRuleSumMinus).Shift(),
@@ -216,7 +216,7 @@ func TestTableItemset_GetNextItemsets(t *testing.T) {
 		t.Fatal("set1sumNext no plus")
 	}
 	if !set1sumPlus.IsEqual(
-		tableItemset{[]tableItem{
+		tableItemset{items: []tableItem{
 			newTableItem(testTableItemsetRuleSumPlus).Shift().Shift(),
 			newTableItem(testTableItemsetRuleValZero),
 			newTableItem(testTableItemsetRuleValOne),
@@ -230,7 +230,7 @@ func TestTableItemset_GetNextItemsets(t *testing.T) {
 		t.Fatal("set1sumNext no minus")
 	}
 	if !set1sumMinus.IsEqual(
-		tableItemset{[]tableItem{
+		tableItemset{items: []tableItem{
 			newTableItem(testTableItemsetRuleSumMinus).Shift().Shift(),
 			newTableItem(testTableItemsetRuleValZero),
 			newTableItem(testTableItemsetRuleValOne),
@@ -0,0 +1,76 @@
+package lr0
+
+// lalrLookaheads calculates LALR(1) lookahead sets for every item in every
+// state of LR(0) automaton by propagating lookaheads through closures and
+// transitions until nothing changes.
+//
+// Item `A : α > B β` with lookahead L spawns lookahead FIRST(β) for every item
+// `B : > γ` in the same state, plus L itself when β is nullable. Shifted item
+// `A : α B > β` in the target state inherits L as is. Since states are LR(0)
+// cores, the result is the same as merging canonical LR(1) states by core.
+func lalrLookaheads(states []tableItemset, rows []*tableRow, g *grammar) []map[tableItem]idSet {
+	sets := g.Sets()
+
+	res := make([]map[tableItem]idSet, len(states))
+	for si, st := range states {
+		m := make(map[tableItem]idSet, len(st.items))
+		for _, it := range st.items {
+			m[it] = newIdSet()
+		}
+		res[si] = m
+	}
+
+	for changed := true; changed; {
+		changed = false
+		for si, st := range states {
+			for _, it := range st.items {
+				if !it.HasFurther() {
+					continue
+				}
+				from := res[si][it]
+				nextId := it.Expected()
+
+				to, ok := rows[si].TerminalAction(nextId)
+				if !ok {
+					to, _ = rows[si].GotoAction(nextId)
+				}
+				if addIdSet(res[to][it.Shift()], from) {
+					changed = true
+				}
+
+				if g.IsTerminal(nextId) {
+					continue
+				}
+				spawn := closureLookahead(it, from, sets)
+				for _, r := range g.RulesFor(nextId) {
+					if addIdSet(res[si][newTableItem(r)], spawn) {
+						changed = true
+					}
+				}
+			}
+		}
+	}
+	return res
+}
+
+// closureLookahead returns lookahead for items `B : > γ` spawned by closure of
+// the given item `A : α > B β` with lookahead `from`, which is FIRST(β) plus
+// `from` itself when β is nullable
+func closureLookahead(it tableItem, from readonlyIdSet, sets *grammarSets) idSet {
+	spawn := newIdSet()
+	if sets.addFirstOf(spawn, it.Definition()[it.nextIndex+1:]) {
+		if it.HasEOF() {
+			spawn.Add(tEof)
+		}
+		addIdSet(spawn, from)
+	}
+	return spawn
+}
+
+// addIdSet adds all Id from `from` into `to` and returns whether `to` was
+// changed
+func addIdSet(to idSet, from readonlyIdSet) bool {
+	before := to.Count()
+	to.Add(from.Ids()...)
+	return to.Count() != before
+}
@@ -0,0 +1,52 @@
+package lr0
+
+// newTableItemsetLR1 creates canonical LR(1) tableItemset from the given
+// kernel items with their lookahead sets
+//
+// Lookaheads of closure items are calculated in the same way as in
+// lalrLookaheads, but within this itemset only.
+func newTableItemsetLR1(items []tableItem, lookahead map[tableItem]idSet, g *grammar) tableItemset {
+	allItems := expandAllPossibleTableItems(items, g)
+	la := make(map[tableItem]idSet, len(allItems))
+	for _, it := range allItems {
+		la[it] = newIdSet()
+		if from, ok := lookahead[it]; ok {
+			addIdSet(la[it], from)
+		}
+	}
+
+	sets := g.Sets()
+	for changed := true; changed; {
+		changed = false
+		for _, it := range allItems {
+			nextId := it.Expected()
+			if nextId == InvalidId || g.IsTerminal(nextId) {
+				continue
+			}
+			spawn := closureLookahead(it, la[it], sets)
+			for _, r := range g.RulesFor(nextId) {
+				if addIdSet(la[newTableItem(r)], spawn) {
+					changed = true
+				}
+			}
+		}
+	}
+
+	return tableItemset{items: allItems, lookahead: la}
+}
+
+// countCores returns count of different LR(0) cores in the given states
+func countCores(states []tableItemset) int {
+	var cores []tableItemset
+NextState:
+	for _, st := range states {
+		core := tableItemset{items: st.items}
+		for _, c := range cores {
+			if c.IsEqual(core) {
+				continue NextState
+			}
+		}
+		cores = append(cores, core)
+	}
+	return len(cores)
+}
@@ -2,126 +2,274 @@ package lr0
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/pkg/errors"
-	"github.com/vovan-ve/go-lr0-parser/internal/helpers"
 )
 
-//// Row is a single row of a Table
-////
-//// https://en.wikipedia.org/wiki/LR_parser#Table_construction
-//type Row interface {
-//	// AcceptEof returns true if this state accepts EOF
-//	AcceptEof() bool
-//	// TerminalsSet returns all terminals possible in this state
-//	TerminalsSet() readonlyIdSet
-//	// TerminalAction returns next state index for the given terminal
-//	TerminalAction(id Id) (tableStateIndex, bool)
-//	// GotoAction returns next state index for the given non-terminal
-//	GotoAction(id Id) (tableStateIndex, bool)
-//	// ReduceRule returns a reduce rule if available or nil otherwise
-//	ReduceRule() Rule
-//	// IsReduceOnly returns true if this state can only be used for reduce
-//	IsReduceOnly() bool
-//}
-
-func newTableRow() *tableRow {
-	return &tableRow{
-		terminalsSet: newIdSet(),
-		terminals:    make(stateActions),
-		gotos:        make(stateActions),
-	}
+// Row is a read-only view of a single row of a Table
+//
+// Sets of Id are returned as sorted slices.
+//
+// https://en.wikipedia.org/wiki/LR_parser#Table_construction
+type Row interface {
+	// AcceptEof returns true if this state accepts EOF
+	AcceptEof() bool
+	// Terminals returns all terminals expected in this state to shift or to
+	// reduce by
+	Terminals() []Id
+	// TerminalAction returns next state index to shift the given terminal
+	TerminalAction(id Id) (int, bool)
+	// Gotos returns all non-terminals having goto action in this state
+	Gotos() []Id
+	// GotoAction returns next state index for the given non-terminal
+	GotoAction(id Id) (int, bool)
+	// ReduceRule returns a rule to reduce regardless of lookahead if
+	// available or nil otherwise
+	ReduceRule() Rule
+	// Lookaheads returns all terminals and EOF having reduce rule bound in
+	// this state
+	Lookaheads() []Id
+	// ReduceRuleFor returns a reduce rule for the given lookahead terminal or
+	// EOF if any, nil otherwise
+	ReduceRuleFor(id Id) Rule
+	// IsDenied returns true if the given terminal is error in this state due
+	// to non-associativity
+	IsDenied(id Id) bool
+	// IsReduceOnly returns true if this state can only be used for reduce.
+	// A state with denied terminals is not, since the next terminal must be
+	// checked.
+	IsReduceOnly() bool
+}
+
+// newTableRow creates new row to build a table. Rules are referred by index
+// in the given list, as TableData does.
+func newTableRow(rules []Rule, index map[Rule]int) *tableRow {
+	return &tableRow{data: RowData{Reduce: -1}, rules: rules, index: index}
 }
 
+var _ Row = (*tableRow)(nil)
+
+// tableRow is a row of table in the same form as RowData, so a loaded table
+// uses RowData as is
 type tableRow struct {
-	acceptEof    bool
-	terminalsSet idSet
-	terminals    stateActions
-	gotos        stateActions
+	data RowData
+	// rules are referred by index from data
+	rules []Rule
+	// index of rules to build the table, nil for loaded table
+	index map[Rule]int
+}
+
+func (r *tableRow) AcceptEof() bool { return r.data.AcceptEof }
+func (r *tableRow) SetAcceptEof()   { r.data.AcceptEof = true }
 
-	reduceRule Rule
+func (r *tableRow) ReduceRule() Rule     { return r.rule(r.data.Reduce) }
+func (r *tableRow) SetReduceRule(v Rule) { r.data.Reduce = r.index[v] }
+
+// ReduceRuleFor returns a reduce rule bound to the given lookahead terminal or
+// tEof. It does not fall back to ReduceRule.
+func (r *tableRow) ReduceRuleFor(id Id) Rule {
+	if ri, ok := findAction(r.data.Lookahead, id); ok {
+		return r.rules[ri]
+	}
+	return nil
 }
 
-func (r *tableRow) AcceptEof() bool { return r.acceptEof }
-func (r *tableRow) SetAcceptEof()   { r.acceptEof = true }
+// SetReduceRuleFor binds a reduce rule to the given lookahead terminal or
+// tEof
+func (r *tableRow) SetReduceRuleFor(id Id, v Rule) {
+	ri := r.index[v]
+	if prev, ok := findAction(r.data.Lookahead, id); ok && prev != ri {
+		panic(errors.Wrap(ErrInternal, "already was set to different rule"))
+	}
+	r.data.Lookahead = setAction(r.data.Lookahead, id, ri)
+}
 
-func (r *tableRow) ReduceRule() Rule     { return r.reduceRule }
-func (r *tableRow) SetReduceRule(v Rule) { r.reduceRule = v }
+func (r *tableRow) rule(index int) Rule {
+	if index < 0 {
+		return nil
+	}
+	return r.rules[index]
+}
 
-func (r *tableRow) TerminalsSet() readonlyIdSet { return r.terminalsSet }
+func (r *tableRow) TerminalsSet() readonlyIdSet { return (*rowTerminals)(r) }
+func (r *tableRow) Terminals() []Id             { return r.TerminalsSet().Ids() }
+func (r *tableRow) Gotos() []Id                 { return actionIds(r.data.Goto) }
+func (r *tableRow) Lookaheads() []Id            { return actionIds(r.data.Lookahead) }
 
 func (r *tableRow) TerminalAction(id Id) (tableStateIndex, bool) {
-	idx, ok := r.terminals[id]
-	return idx, ok
+	return findAction(r.data.Shift, id)
 }
 
 func (r *tableRow) SetTerminalAction(id Id, idx tableStateIndex) {
 	// impossible to predict or check order of overlapping terminals here
 	// example is plus `+` and increment `++`
 	// a `+` can incorrectly match a part of increment `++` which is incorrect
-	if v, ok := r.terminals[id]; ok && v != idx {
+	if v, ok := findAction(r.data.Shift, id); ok && v != idx {
 		panic(errors.Wrap(ErrInternal, "already was set to different index"))
 	}
-	r.terminalsSet.Add(id)
-	r.terminals[id] = idx
+	r.data.Shift = setAction(r.data.Shift, id, idx)
+}
+
+// RemoveTerminalAction removes shift action for the given terminal, when
+// the conflict was resolved by precedence to not shift
+func (r *tableRow) RemoveTerminalAction(id Id) {
+	r.data.Shift = removeAction(r.data.Shift, id)
+}
+
+// IsDenied returns true if the given terminal is error in this row due to
+// non-associativity
+func (r *tableRow) IsDenied(id Id) bool {
+	i := sort.Search(len(r.data.Denied), func(i int) bool { return r.data.Denied[i] >= id })
+	return i < len(r.data.Denied) && r.data.Denied[i] == id
+}
+
+// Deny marks the given terminal as error in this row
+func (r *tableRow) Deny(id Id) {
+	r.RemoveTerminalAction(id)
+	if r.IsDenied(id) {
+		return
+	}
+	i := sort.Search(len(r.data.Denied), func(i int) bool { return r.data.Denied[i] >= id })
+	r.data.Denied = append(r.data.Denied, 0)
+	copy(r.data.Denied[i+1:], r.data.Denied[i:])
+	r.data.Denied[i] = id
 }
 
 func (r *tableRow) GotoAction(id Id) (tableStateIndex, bool) {
-	idx, ok := r.gotos[id]
-	return idx, ok
+	return findAction(r.data.Goto, id)
 }
 
 func (r *tableRow) SetGoto(id Id, idx tableStateIndex) {
-	if v, ok := r.gotos[id]; ok && v != idx {
+	if v, ok := findAction(r.data.Goto, id); ok && v != idx {
 		panic(errors.Wrap(ErrInternal, "already was set to different index"))
 	}
-	r.gotos[id] = idx
+	r.data.Goto = setAction(r.data.Goto, id, idx)
 }
 
 func (r *tableRow) IsReduceOnly() bool {
-	return !r.acceptEof &&
-		len(r.terminals) == 0 &&
-		len(r.gotos) == 0 &&
-		r.reduceRule != nil
+	return !r.data.AcceptEof &&
+		len(r.data.Shift) == 0 &&
+		len(r.data.Goto) == 0 &&
+		len(r.data.Denied) == 0 &&
+		r.data.Reduce != -1
 }
 
 func (r *tableRow) dump(indent string, reg SymbolRegistry) string {
 	res := indent + "EOF: "
-	if r.acceptEof {
+	if r.AcceptEof() {
 		res += "-"
 	} else {
 		res += "ACCEPT"
 	}
 
 	res += "\n" + indent + "terminals:"
-	if len(r.terminals) != 0 {
-		res += "\n" + r.terminals.dump(indent+"\t", reg)
+	if len(r.data.Shift) != 0 {
+		res += "\n" + dumpActions(r.data.Shift, indent+"\t", reg, nil)
 	} else {
 		res += " -\n"
 	}
 
 	res += indent + "goto:"
-	if len(r.gotos) != 0 {
-		res += "\n" + r.gotos.dump(indent+"\t", reg)
+	if len(r.data.Goto) != 0 {
+		res += "\n" + dumpActions(r.data.Goto, indent+"\t", reg, nil)
 	} else {
 		res += " -\n"
 	}
 
 	res += indent + "rule:"
-	if r.reduceRule != nil {
-		res += "\n" + indent + "\t" + r.ReduceRule().String() + "\n"
+	if rule := r.ReduceRule(); rule != nil {
+		res += "\n" + indent + "\t" + rule.String() + "\n"
 	} else {
 		res += " -\n"
 	}
+
+	if len(r.data.Lookahead) != 0 {
+		res += indent + "lookahead:\n" + dumpActions(r.data.Lookahead, indent+"\t", reg, r.rules)
+	}
+	if len(r.data.Denied) != 0 {
+		res += indent + "denied:"
+		for _, id := range r.data.Denied {
+			res += " " + dumpId(id, reg)
+		}
+		res += "\n"
+	}
 	return res
 }
 
-type stateActions map[Id]tableStateIndex
+// rowTerminals is a set of terminals expected in a row: shifted ones and
+// lookaheads except EOF
+type rowTerminals tableRow
+
+func (s *rowTerminals) Has(id Id) bool {
+	if _, ok := findAction(s.data.Shift, id); ok {
+		return true
+	}
+	_, ok := findAction(s.data.Lookahead, id)
+	return ok && id != tEof
+}
+
+func (s *rowTerminals) Count() int { return len(s.Ids()) }
+
+func (s *rowTerminals) Ids() []Id {
+	set := newIdSet(actionIds(s.data.Shift)...)
+	for _, a := range s.data.Lookahead {
+		if a.Id != tEof {
+			set.Add(a.Id)
+		}
+	}
+	return set.Ids()
+}
+
+// findAction returns target of action for the given Id in the list sorted by
+// Id
+func findAction(list []Action, id Id) (int, bool) {
+	i := sort.Search(len(list), func(i int) bool { return list[i].Id >= id })
+	if i < len(list) && list[i].Id == id {
+		return list[i].To, true
+	}
+	return 0, false
+}
+
+// setAction sets action for the given Id in the list sorted by Id
+func setAction(list []Action, id Id, to int) []Action {
+	i := sort.Search(len(list), func(i int) bool { return list[i].Id >= id })
+	if i < len(list) && list[i].Id == id {
+		list[i].To = to
+		return list
+	}
+	list = append(list, Action{})
+	copy(list[i+1:], list[i:])
+	list[i] = Action{Id: id, To: to}
+	return list
+}
+
+// removeAction removes action for the given Id from the list sorted by Id
+func removeAction(list []Action, id Id) []Action {
+	i := sort.Search(len(list), func(i int) bool { return list[i].Id >= id })
+	if i < len(list) && list[i].Id == id {
+		list = append(list[:i], list[i+1:]...)
+	}
+	return list
+}
+
+func actionIds(list []Action) []Id {
+	ids := make([]Id, 0, len(list))
+	for _, a := range list {
+		ids = append(ids, a.Id)
+	}
+	return ids
+}
 
-func (s stateActions) dump(indent string, r SymbolRegistry) string {
+// dumpActions dumps actions to states, or to rules when they are given
+func dumpActions(list []Action, indent string, reg SymbolRegistry, rules []Rule) string {
 	res := ""
-	for _, p := range helpers.MapSortedInt(s) {
-		res += indent + fmt.Sprintf("%s -> %v\n", dumpId(p.K, r), p.V)
+	for _, a := range list {
+		if rules != nil {
+			res += indent + fmt.Sprintf("%s -> %s\n", dumpId(a.Id, reg), rules[a.To])
+		} else {
+			res += indent + fmt.Sprintf("%s -> %v\n", dumpId(a.Id, reg), a.To)
+		}
 	}
 	return res
 }
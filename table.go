@@ -8,26 +8,82 @@ import (
 
 type tableStateIndex = int
 
-//// Table is states table controlling how parses state will behave
-////
-//// https://en.wikipedia.org/wiki/LR_parser#Table_construction
-//type Table interface {
-//	//RowsCount() int
+// Table is a read-only view of states table controlling how parser will
+// behave
 //
-//	Row(idx tableStateIndex) Row
-//}
+// https://en.wikipedia.org/wiki/LR_parser#Table_construction
+type Table interface {
+	// RowsCount returns count of states
+	RowsCount() int
+	// Row returns a row of the state with the given index. First states are
+	// initial for main rules in order of Grammar.MainRules(), so the state 0
+	// is initial for the first one.
+	Row(index int) Row
+	// Stats returns size of the table
+	Stats() TableStats
+	// Data returns the table in plain form to serialize, see WithTable
+	Data() *TableData
+	// Items returns items of the state with the given index rendered with
+	// `>` pointing to the current position. Final items in lookahead modes
+	// have lookahead terminals in brackets.
+	//
+	//	Sum : Sum > "+" Val
+	//	Val : int > [$ "+"]
+	//
+	// Items are not kept in TableData, so Items returns nil for a table
+	// loaded by WithTable.
+	Items(index int) []string
+}
 
 // newTable creates new Table from the given Grammar
-func newTable(g *grammar) *table {
+//
+// Table mode can be set by WithMode option. Conflicts are reported by panic
+// with *ConflictError since it's grammar definition problem.
+func newTable(g *grammar, opts ...Option) *table {
+	t, errs := newTableE(g, opts...)
+	if len(errs) != 0 {
+		panic(errs[0])
+	}
+	return t
+}
+
+// newTableE creates new Table like newTable does, but returns all conflicts
+// and other definition errors found instead of panic
+func newTableE(g *grammar, opts ...Option) (*table, []error) {
+	c := newConfig(opts)
+	if c.table != nil {
+		t, err := loadTable(g, c, c.table)
+		if err != nil {
+			return nil, []error{err}
+		}
+		return t, nil
+	}
 	type statesMap = map[tableStateIndex]map[Id]tableItemset
 	var (
-		rows   []*tableRow
-		states []tableItemset
+		rows      []*tableRow
+		states    []tableItemset
+		ruleIndex = make(map[Rule]int, len(g.rules))
 	)
+	for i, r := range g.rules {
+		ruleIndex[r] = i
+	}
 
+	// every main rule has own initial state, so they go first in the same
+	// order, and other states are shared
 	addStates := make(statesMap)
-	addStates[0] = map[Id]tableItemset{
-		InvalidId: newTableItemset([]tableItem{newTableItem(g.MainRule())}, g),
+	for _, r := range g.MainRules() {
+		initItems := []tableItem{newTableItem(r)}
+		initState := newTableItemset(initItems, g)
+		if c.mode == LR1 {
+			initState = newTableItemsetLR1(initItems, nil, g)
+		}
+		newR := newTableRow(g.rules, ruleIndex)
+		if initState.HasFinalItem() {
+			newR.SetAcceptEof()
+		}
+		addStates[len(states)] = initState.GetNextItemsets(g)
+		states = append(states, initState)
+		rows = append(rows, newR)
 	}
 	for len(addStates) != 0 {
 		nextStates := make(statesMap)
@@ -38,17 +94,13 @@ func newTable(g *grammar) *table {
 		NewSets:
 			for _, from := range helpers.MapSortedInt(fromSets.V) {
 				fromId, fromState := from.K, from.V
-				var fromIsT, fromIsNT bool
-				if fromId != InvalidId {
-					fromIsT = g.IsTerminal(fromId)
-					fromIsNT = !fromIsT
-				}
+				fromIsT := g.IsTerminal(fromId)
 				for si, st := range states {
 					if st.IsEqual(fromState) {
 						thatRow := rows[fromSI]
 						if fromIsT {
 							thatRow.SetTerminalAction(fromId, si)
-						} else if fromIsNT {
+						} else {
 							thatRow.SetGoto(fromId, si)
 						}
 						continue NewSets
@@ -56,7 +108,7 @@ func newTable(g *grammar) *table {
 				}
 
 				newSI := len(states)
-				newR := newTableRow()
+				newR := newTableRow(g.rules, ruleIndex)
 				if fromState.HasFinalItem() {
 					newR.SetAcceptEof()
 				}
@@ -68,7 +120,7 @@ func newTable(g *grammar) *table {
 				fromRow := rows[fromSI]
 				if fromIsT {
 					fromRow.SetTerminalAction(fromId, newSI)
-				} else if fromIsNT {
+				} else {
 					fromRow.SetGoto(fromId, newSI)
 				}
 			}
@@ -76,22 +128,205 @@ func newTable(g *grammar) *table {
 		addStates = nextStates
 	}
 
+	stats := TableStats{Mode: c.mode, States: len(states), Cores: len(states)}
+	prefixes := shortestPrefixes(rows, len(g.mainIndices))
+	// they are needed for conflicts only
+	var sentences map[Id][]Id
+
+	var (
+		errs      []error
+		failed    = make(map[string]struct{})
+		lookahead func(si tableStateIndex, it tableItem) readonlyIdSet
+	)
+	switch c.mode {
+	case LR1:
+		stats.Cores = countCores(states)
+		lookahead = func(si tableStateIndex, it tableItem) readonlyIdSet {
+			return states[si].lookahead[it]
+		}
+	case LALR1:
+		lookaheads := lalrLookaheads(states, rows, g)
+		lookahead = func(si tableStateIndex, it tableItem) readonlyIdSet {
+			return lookaheads[si][it]
+		}
+	case SLR1:
+		sets := g.Sets()
+		lookahead = func(_ tableStateIndex, it tableItem) readonlyIdSet {
+			return sets.Follow(it.Subject())
+		}
+	}
+
 	for si, st := range states {
-		if r := st.ReduceRule(); r != nil {
-			rows[si].SetReduceRule(r)
+		var (
+			found       []*conflict
+			itLookahead func(tableItem) readonlyIdSet
+		)
+		// precedence problems are reported by panic
+		err := catchDefine(func() {
+			if lookahead != nil {
+				itLookahead = func(it tableItem) readonlyIdSet { return lookahead(si, it) }
+				found = setLookaheadReduces(rows[si], st, g, c, itLookahead)
+			} else {
+				r := st.ReduceRule()
+				if r != nil && !r.HasEOF() {
+					resolveLR0ShiftReduce(rows[si], r, g, c)
+				}
+				found = validateTableItemsetDeterministic(st.items, rows[si].TerminalsSet(), g)
+				// LR(0) row reduces only by default, so denied terminals are
+				// checked by parser before, see Row.IsReduceOnly
+				if r != nil {
+					rows[si].SetReduceRule(r)
+				}
+			}
+		})
+		if err != nil {
+			// same rule can fail in many states
+			if _, ok := failed[err.Error()]; !ok {
+				failed[err.Error()] = struct{}{}
+				errs = append(errs, err)
+			}
+			continue
+		}
+		if len(found) != 0 && sentences == nil {
+			sentences = shortestSentences(g)
+		}
+		for _, cf := range found {
+			errs = append(errs, newConflictError(cf, si, st, expandPrefix(prefixes[si], sentences), g, itLookahead))
+		}
+	}
+
+	return &table{
+		rows:        rows,
+		states:      states,
+		stats:       stats,
+		g:           g,
+		fingerprint: grammarFingerprint(g, c),
+	}, errs
+}
+
+// setLookaheadReduces binds reduce rules of final items in the tableItemset to
+// lookahead terminals in the tableRow. Returns found conflicts:
+//
+// - ErrConflictShiftReduce when a lookahead terminal can be shifted too, or
+// when EOF lookahead meets accepted EOF. Such conflict can be resolved by
+// precedence, see Left.
+//
+// - ErrConflictReduceReduce when different rules have common lookahead
+//
+// A row which has the only rule to reduce and nothing to shift or deny will
+// reduce it by default without looking ahead, like LR(0) row does.
+func setLookaheadReduces(row *tableRow, st tableItemset, g *grammar, c *config, lookahead func(tableItem) readonlyIdSet) []*conflict {
+	var (
+		reduceRules []Rule
+		sr          = newConflict(ErrConflictShiftReduce)
+		rr          = newConflict(ErrConflictReduceReduce)
+	)
+	for _, it := range st.items {
+		if it.HasFurther() || it.HasEOF() {
+			continue
+		}
+		reduceRules = append(reduceRules, it.Rule)
+		for _, id := range lookahead(it).Ids() {
+			if row.IsDenied(id) {
+				continue
+			}
+			if _, ok := row.TerminalAction(id); ok {
+				switch c.resolveShiftReduce(id, it.Rule, g) {
+				case resolveShift:
+					continue
+				case resolveReduce:
+					row.RemoveTerminalAction(id)
+				case resolveError:
+					row.Deny(id)
+					continue
+				default:
+					sr.add(id, it.Rule)
+					sr.add(id, st.rulesExpecting(id)...)
+					continue
+				}
+			}
+			if id == tEof && row.AcceptEof() {
+				sr.add(id, it.Rule)
+				sr.add(id, st.rulesExpecting(id)...)
+				continue
+			}
+			if prev := row.ReduceRuleFor(id); prev != nil && prev != it.Rule {
+				rr.add(id, prev, it.Rule)
+				continue
+			}
+			row.SetReduceRuleFor(id, it.Rule)
 		}
 	}
+	if len(reduceRules) == 1 && len(row.data.Shift) == 0 && len(row.data.Denied) == 0 && !row.AcceptEof() {
+		row.SetReduceRule(reduceRules[0])
+	}
 
-	return &table{rows: rows}
+	var found []*conflict
+	for _, cf := range []*conflict{sr, rr} {
+		if !cf.isEmpty() {
+			found = append(found, cf)
+		}
+	}
+	return found
+}
+
+// resolveLR0ShiftReduce resolves by precedence conflicts of shift actions with
+// the rule which LR(0) row will reduce on any terminal not shifted.
+//
+// When the rule wins, the shift action is removed, so the terminal will cause
+// reduce. Conflicts without precedence stay as is, so shift is preferred, like
+// it always was in LR(0) table.
+func resolveLR0ShiftReduce(row *tableRow, r Rule, g *grammar, c *config) {
+	if len(c.precedence) == 0 {
+		return
+	}
+	for _, id := range row.TerminalsSet().Ids() {
+		switch c.resolveShiftReduce(id, r, g) {
+		case resolveReduce:
+			row.RemoveTerminalAction(id)
+		case resolveError:
+			row.Deny(id)
+		}
+	}
 }
 
 type table struct {
-	rows []*tableRow
+	rows   []*tableRow
+	states []tableItemset
+	stats  TableStats
+	g      *grammar
+	// fingerprint of grammar and options for TableData
+	fingerprint string
 }
 
-//func (t *table) RowsCount() int { return len(t.rows) }
+var _ Table = (*table)(nil)
+
+func (t *table) RowsCount() int              { return len(t.rows) }
+func (t *table) Row(idx tableStateIndex) Row { return t.rows[idx] }
+func (t *table) Stats() TableStats           { return t.stats }
 
-func (t *table) Row(idx tableStateIndex) *tableRow { return t.rows[idx] }
+func (t *table) Items(idx tableStateIndex) []string {
+	if t.states == nil {
+		return nil
+	}
+	row := t.rows[idx]
+	items := t.states[idx].items
+	res := make([]string, 0, len(items))
+	for _, it := range items {
+		s := it.dump(t.g)
+		if t.stats.Mode != LR0 && !it.HasFurther() && !it.HasEOF() {
+			var la []Id
+			for _, id := range row.Lookaheads() {
+				if row.ReduceRuleFor(id) == it.Rule {
+					la = append(la, id)
+				}
+			}
+			s += " [" + dumpIds(la, t.g) + "]"
+		}
+		res = append(res, s)
+	}
+	return res
+}
 
 func (t *table) dump(reg SymbolRegistry) string {
 	res := "====[ table ]====\n"
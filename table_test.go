@@ -2,6 +2,8 @@ package lr0
 
 import (
 	"testing"
+
+	"github.com/vovan-ve/go-lr0-parser/internal/testutils"
 )
 
 func TestTable(t *testing.T) {
@@ -11,6 +13,9 @@ func TestTable(t *testing.T) {
 	if len(tbl.rows) != rowsCount {
 		t.Errorf("rows are %v", len(tbl.rows))
 	}
+	if tbl.stats != (TableStats{Mode: LR0, States: rowsCount, Cores: rowsCount}) {
+		t.Errorf("stats: %+v", tbl.stats)
+	}
 
 	// row 0 (Goal : > Sum) ==================================================
 
@@ -21,8 +26,8 @@ func TestTable(t *testing.T) {
 	if row0.ReduceRule() != nil {
 		t.Error("row0 reduce rule")
 	}
-	if len(row0.terminals) != 2 {
-		t.Errorf("row0.terminals: %#v", row0.terminals)
+	if len(row0.data.Shift) != 2 {
+		t.Errorf("row0.data.Shift: %#v", row0.data.Shift)
 	}
 	var (
 		ok        bool
@@ -31,20 +36,20 @@ func TestTable(t *testing.T) {
 		next0val  tableStateIndex
 		next0sum  tableStateIndex
 	)
-	if next0zero, ok = row0.terminals[tZero]; !ok || next0zero < 0 || next0zero >= rowsCount {
-		t.Errorf("row0.terminals zero: %v, %v", next0zero, ok)
+	if next0zero, ok = row0.TerminalAction(tZero); !ok || next0zero < 0 || next0zero >= rowsCount {
+		t.Errorf("row0.data.Shift zero: %v, %v", next0zero, ok)
 	}
-	if next0one, ok = row0.terminals[tOne]; !ok || next0one < 0 || next0one >= rowsCount {
-		t.Errorf("row0.terminals one: %v, %v", next0one, ok)
+	if next0one, ok = row0.TerminalAction(tOne); !ok || next0one < 0 || next0one >= rowsCount {
+		t.Errorf("row0.data.Shift one: %v, %v", next0one, ok)
 	}
-	if len(row0.gotos) != 2 {
-		t.Errorf("row0.gotos: %#v", row0.gotos)
+	if len(row0.data.Goto) != 2 {
+		t.Errorf("row0.data.Goto: %#v", row0.data.Goto)
 	}
-	if next0val, ok = row0.gotos[nVal]; !ok || next0val < 0 || next0val >= rowsCount {
-		t.Errorf("row0.gotos val: %v, %v", next0val, ok)
+	if next0val, ok = row0.GotoAction(nVal); !ok || next0val < 0 || next0val >= rowsCount {
+		t.Errorf("row0.data.Goto val: %v, %v", next0val, ok)
 	}
-	if next0sum, ok = row0.gotos[nSum]; !ok || next0sum < 0 || next0sum >= rowsCount {
-		t.Errorf("row0.gotos sum: %v, %v", next0sum, ok)
+	if next0sum, ok = row0.GotoAction(nSum); !ok || next0sum < 0 || next0sum >= rowsCount {
+		t.Errorf("row0.data.Goto sum: %v, %v", next0sum, ok)
 	}
 	if len(map[tableStateIndex]struct{}{next0zero: {}, next0one: {}, next0val: {}, next0sum: {}}) != 4 {
 		t.Errorf("row0 actions are not uniq: %v, %v, %v, %v", next0zero, next0one, next0val, next0sum)
@@ -94,17 +99,17 @@ func TestTable(t *testing.T) {
 		nextGoalPlus  tableStateIndex
 		nextGoalMinus tableStateIndex
 	)
-	if len(rowGoal.terminals) != 2 {
-		t.Errorf("rowGoal.terminals: %#v", rowGoal.terminals)
+	if len(rowGoal.data.Shift) != 2 {
+		t.Errorf("rowGoal.data.Shift: %#v", rowGoal.data.Shift)
 	}
-	if nextGoalPlus, ok = rowGoal.terminals[tPlus]; !ok || nextGoalPlus < 0 || nextGoalPlus >= rowsCount {
-		t.Errorf("rowGoal.terminals plus: %v, %v", nextGoalPlus, ok)
+	if nextGoalPlus, ok = rowGoal.TerminalAction(tPlus); !ok || nextGoalPlus < 0 || nextGoalPlus >= rowsCount {
+		t.Errorf("rowGoal.data.Shift plus: %v, %v", nextGoalPlus, ok)
 	}
-	if nextGoalMinus, ok = rowGoal.terminals[tMinus]; !ok || nextGoalMinus < 0 || nextGoalMinus >= rowsCount {
-		t.Errorf("rowGoal.terminals minus: %v, %v", nextGoalMinus, ok)
+	if nextGoalMinus, ok = rowGoal.TerminalAction(tMinus); !ok || nextGoalMinus < 0 || nextGoalMinus >= rowsCount {
+		t.Errorf("rowGoal.data.Shift minus: %v, %v", nextGoalMinus, ok)
 	}
-	if len(rowGoal.gotos) != 0 {
-		t.Errorf("rowGoal.gotos: %#v", rowGoal.gotos)
+	if len(rowGoal.data.Goto) != 0 {
+		t.Errorf("rowGoal.data.Goto: %#v", rowGoal.data.Goto)
 	}
 	if len(map[tableStateIndex]struct{}{nextGoalPlus: {}, nextGoalMinus: {}}) != 2 {
 		t.Errorf("rowGoal actions are not uniq: %v, %v", nextGoalPlus, nextGoalMinus)
@@ -124,20 +129,20 @@ func TestTable(t *testing.T) {
 		nextPlusOne  tableStateIndex
 		nextPlusVal  tableStateIndex
 	)
-	if len(rowSumPlus.terminals) != 2 {
-		t.Errorf("rowSumPlus.terminals: %#v", rowSumPlus.terminals)
+	if len(rowSumPlus.data.Shift) != 2 {
+		t.Errorf("rowSumPlus.data.Shift: %#v", rowSumPlus.data.Shift)
 	}
-	if nextPlusZero, ok = rowSumPlus.terminals[tZero]; !ok || nextPlusZero < 0 || nextPlusZero >= rowsCount {
-		t.Errorf("rowSumPlus.terminals zero: %v, %v", nextPlusZero, ok)
+	if nextPlusZero, ok = rowSumPlus.TerminalAction(tZero); !ok || nextPlusZero < 0 || nextPlusZero >= rowsCount {
+		t.Errorf("rowSumPlus.data.Shift zero: %v, %v", nextPlusZero, ok)
 	}
-	if nextPlusOne, ok = rowSumPlus.terminals[tOne]; !ok || nextPlusOne < 0 || nextPlusOne >= rowsCount {
-		t.Errorf("rowSumPlus.terminals one: %v, %v", nextPlusOne, ok)
+	if nextPlusOne, ok = rowSumPlus.TerminalAction(tOne); !ok || nextPlusOne < 0 || nextPlusOne >= rowsCount {
+		t.Errorf("rowSumPlus.data.Shift one: %v, %v", nextPlusOne, ok)
 	}
-	if len(rowSumPlus.gotos) != 1 {
-		t.Errorf("rowSumPlus.gotos: %#v", rowSumPlus.gotos)
+	if len(rowSumPlus.data.Goto) != 1 {
+		t.Errorf("rowSumPlus.data.Goto: %#v", rowSumPlus.data.Goto)
 	}
-	if nextPlusVal, ok = rowSumPlus.gotos[nVal]; !ok || nextPlusVal < 0 || nextPlusVal >= rowsCount {
-		t.Errorf("rowSumPlus.gotos val: %v, %v", nextPlusVal, ok)
+	if nextPlusVal, ok = rowSumPlus.GotoAction(nVal); !ok || nextPlusVal < 0 || nextPlusVal >= rowsCount {
+		t.Errorf("rowSumPlus.data.Goto val: %v, %v", nextPlusVal, ok)
 	}
 	if len(map[tableStateIndex]struct{}{nextPlusZero: {}, nextPlusOne: {}, nextPlusVal: {}}) != 3 {
 		t.Errorf("rowSumPlus actions are not uniq: %v, %v, %v", nextPlusZero, nextPlusOne, nextPlusVal)
@@ -157,20 +162,20 @@ func TestTable(t *testing.T) {
 		nextMinusOne  tableStateIndex
 		nextMinusVal  tableStateIndex
 	)
-	if len(rowSumMinus.terminals) != 2 {
-		t.Errorf("rowSumMinus.terminals: %#v", rowSumMinus.terminals)
+	if len(rowSumMinus.data.Shift) != 2 {
+		t.Errorf("rowSumMinus.data.Shift: %#v", rowSumMinus.data.Shift)
 	}
-	if nextMinusZero, ok = rowSumMinus.terminals[tZero]; !ok || nextMinusZero < 0 || nextMinusZero >= rowsCount {
-		t.Errorf("rowSumMinus.terminals zero: %v, %v", nextMinusZero, ok)
+	if nextMinusZero, ok = rowSumMinus.TerminalAction(tZero); !ok || nextMinusZero < 0 || nextMinusZero >= rowsCount {
+		t.Errorf("rowSumMinus.data.Shift zero: %v, %v", nextMinusZero, ok)
 	}
-	if nextMinusOne, ok = rowSumMinus.terminals[tOne]; !ok || nextMinusOne < 0 || nextMinusOne >= rowsCount {
-		t.Errorf("rowSumMinus.terminals one: %v, %v", nextMinusOne, ok)
+	if nextMinusOne, ok = rowSumMinus.TerminalAction(tOne); !ok || nextMinusOne < 0 || nextMinusOne >= rowsCount {
+		t.Errorf("rowSumMinus.data.Shift one: %v, %v", nextMinusOne, ok)
 	}
-	if len(rowSumMinus.gotos) != 1 {
-		t.Errorf("rowSumMinus.gotos: %#v", rowSumMinus.gotos)
+	if len(rowSumMinus.data.Goto) != 1 {
+		t.Errorf("rowSumMinus.data.Goto: %#v", rowSumMinus.data.Goto)
 	}
-	if nextMinusVal, ok = rowSumMinus.gotos[nVal]; !ok || nextMinusVal < 0 || nextMinusVal >= rowsCount {
-		t.Errorf("rowSumMinus.gotos val: %v, %v", nextMinusVal, ok)
+	if nextMinusVal, ok = rowSumMinus.GotoAction(nVal); !ok || nextMinusVal < 0 || nextMinusVal >= rowsCount {
+		t.Errorf("rowSumMinus.data.Goto val: %v, %v", nextMinusVal, ok)
 	}
 	if len(map[tableStateIndex]struct{}{nextMinusZero: {}, nextMinusOne: {}, nextMinusVal: {}}) != 3 {
 		t.Errorf("rowSumMinus actions are not uniq: %v, %v, %v", nextMinusZero, nextMinusOne, nextMinusVal)
@@ -266,3 +271,199 @@ row 8 ---------
 		t.Error("table dump is:\n", d)
 	}
 }
+
+func TestTable_SLR1(t *testing.T) {
+	// Goal : S $
+	// S    : Val zero | Sum one
+	// Val  : plus
+	// Sum  : plus
+	g := newGrammar(
+		[]Terminal{
+			NewTerm(tZero, "zero").Str("0"),
+			NewTerm(tOne, "one").Str("1"),
+			NewTerm(tPlus, `"+"`).Str("+"),
+		},
+		[]NonTerminalDefinition{
+			NewNT(nGoal, "Goal").Main().Is(nProd),
+			NewNT(nProd, "S").
+				Is(nVal, tZero).Do(calc2AnyFirst).
+				Is(nSum, tOne).Do(calc2AnyFirst),
+			NewNT(nVal, "Val").Is(tPlus),
+			NewNT(nSum, "Sum").Is(tPlus),
+		},
+	)
+
+	t.Run("LR0 conflict", func(t *testing.T) {
+		defer testutils.ExpectPanicError(t, ErrConflictReduceReduce)
+		newTable(g)
+	})
+
+	tbl := newTable(g, WithMode(SLR1))
+	plus, ok := tbl.Row(0).TerminalAction(tPlus)
+	if !ok {
+		t.Fatal("no plus in row 0")
+	}
+	row := tbl.rows[plus]
+	if row.ReduceRule() != nil {
+		t.Error("default reduce rule:", row.ReduceRule())
+	}
+	if r := row.ReduceRuleFor(tZero); r == nil || r.Subject() != nVal {
+		t.Error("reduce rule for zero:", r)
+	}
+	if r := row.ReduceRuleFor(tOne); r == nil || r.Subject() != nSum {
+		t.Error("reduce rule for one:", r)
+	}
+	if r := row.ReduceRuleFor(tEof); r != nil {
+		t.Error("reduce rule for EOF:", r)
+	}
+	if s := row.TerminalsSet(); s.Count() != 2 || !s.Has(tZero) || !s.Has(tOne) {
+		t.Errorf("terminals set: %v", s.Ids())
+	}
+
+	const expectRowDump = `EOF: ACCEPT
+terminals: -
+goto: -
+rule: -
+lookahead:
+	zero -> Val : "+"
+	one -> Sum : "+"
+`
+	if d := row.dump("", g); d != expectRowDump {
+		t.Error("row dump is:\n", d)
+	}
+
+	t.Run("shift-reduce", func(t *testing.T) {
+		// Goal : S $
+		// S    : plus S | plus
+		g := newGrammar(
+			[]Terminal{
+				NewTerm(tPlus, `"+"`).Str("+"),
+			},
+			[]NonTerminalDefinition{
+				NewNT(nGoal, "Goal").Main().Is(nSum),
+				NewNT(nSum, "S").
+					Is(tPlus, nSum).Do(calc2AnyFirst).
+					Is(tPlus),
+			},
+		)
+		func() {
+			defer testutils.ExpectPanicError(t, ErrConflictShiftReduce)
+			newTable(g)
+		}()
+		newTable(g, WithMode(SLR1))
+	})
+
+	t.Run("SLR1 conflict", func(t *testing.T) {
+		// Goal : S $
+		// S    : S plus S | zero
+		defer testutils.ExpectPanicError(t, ErrConflictShiftReduce)
+		newTable(newGrammar(
+			[]Terminal{
+				NewTerm(tZero, "zero").Str("0"),
+				NewTerm(tPlus, `"+"`).Str("+"),
+			},
+			[]NonTerminalDefinition{
+				NewNT(nGoal, "Goal").Main().Is(nSum),
+				NewNT(nSum, "S").
+					Is(nSum, tPlus, nSum).Do(calc3AnyNil).
+					Is(tZero),
+			},
+		), WithMode(SLR1))
+	})
+}
+
+func TestTable_LALR1(t *testing.T) {
+	// Goal : S $
+	// S    : L "=" R | R
+	// L    : "*" R | id
+	// R    : L
+	g := newGrammar(
+		[]Terminal{
+			NewTerm(tIdent, "id").Func(matchIdentifier),
+			NewTerm(tMinus, `"="`).Str("="),
+			NewTerm(tMul, `"*"`).Str("*"),
+		},
+		[]NonTerminalDefinition{
+			NewNT(nGoal, "Goal").Main().Is(nSum),
+			NewNT(nSum, "S").
+				Is(nProd, tMinus, nVal).Do(calc3AnyNil).
+				Is(nVal),
+			NewNT(nProd, "L").
+				Is(tMul, nVal).Do(calc2AnyFirst).
+				Is(tIdent),
+			NewNT(nVal, "R").Is(nProd),
+		},
+	)
+
+	t.Run("SLR1 conflict", func(t *testing.T) {
+		defer testutils.ExpectPanicError(t, ErrConflictShiftReduce)
+		newTable(g, WithMode(SLR1))
+	})
+
+	tbl := newTable(g, WithMode(LALR1))
+	if n := len(tbl.rows); n != len(newTable(g, WithMode(LR0)).rows) {
+		t.Errorf("rows count %v differs from LR0", n)
+	}
+
+	l, ok := tbl.Row(0).GotoAction(nProd)
+	if !ok {
+		t.Fatal("no L in row 0")
+	}
+	row := tbl.Row(l)
+	if _, ok := row.TerminalAction(tMinus); !ok {
+		t.Error("no shift `=`")
+	}
+	if r := row.ReduceRuleFor(tEof); r == nil || r.Subject() != nVal {
+		t.Error("reduce rule for EOF:", r)
+	}
+	if r := row.ReduceRuleFor(tMinus); r != nil {
+		t.Error("reduce rule for `=`:", r)
+	}
+}
+
+func TestTable_LR1(t *testing.T) {
+	// Goal : S $
+	// S    : a A d | b B d | a B e | b A e
+	// A    : c
+	// B    : c
+	g := newGrammar(
+		[]Terminal{
+			NewTerm(tZero, "a").Str("a"),
+			NewTerm(tOne, "b").Str("b"),
+			NewTerm(tPlus, "c").Str("c"),
+			NewTerm(tMinus, "d").Str("d"),
+			NewTerm(tMul, "e").Str("e"),
+		},
+		[]NonTerminalDefinition{
+			NewNT(nGoal, "Goal").Main().Is(nSum),
+			NewNT(nSum, "S").
+				Is(tZero, nProd, tMinus).Do(calc3AnyNil).
+				Is(tOne, nVal, tMinus).Do(calc3AnyNil).
+				Is(tZero, nVal, tMul).Do(calc3AnyNil).
+				Is(tOne, nProd, tMul).Do(calc3AnyNil),
+			NewNT(nProd, "A").Is(tPlus),
+			NewNT(nVal, "B").Is(tPlus),
+		},
+	)
+
+	t.Run("LALR1 conflict", func(t *testing.T) {
+		defer testutils.ExpectPanicError(t, ErrConflictReduceReduce)
+		newTable(g, WithMode(LALR1))
+	})
+
+	tbl := newTable(g, WithMode(LR1))
+	// the only state `A : c >, B : c >` is split in two
+	if tbl.stats != (TableStats{Mode: LR1, States: 14, Cores: 13}) || len(tbl.rows) != 14 {
+		t.Errorf("LR1 stats: %+v", tbl.stats)
+	}
+
+	for _, a := range []Id{tZero, tOne} {
+		si, _ := tbl.Row(0).TerminalAction(a)
+		si, _ = tbl.Row(si).TerminalAction(tPlus)
+		row := tbl.Row(si)
+		rd, re := row.ReduceRuleFor(tMinus), row.ReduceRuleFor(tMul)
+		if rd == nil || re == nil || rd == re {
+			t.Errorf("after %s c: %v, %v", dumpId(a, g), rd, re)
+		}
+	}
+}
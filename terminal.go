@@ -8,10 +8,15 @@ import (
 
 const (
 	tWhitespace Id = -iota - 1
+	// tEof is a pseudo-terminal to refer EOF in lookahead sets
+	tEof
+	// tError is a pseudo-terminal which parser shifts recovering from error
+	tError
 )
 
 var (
-	metaWS = term{id: tWhitespace, name: "whitespace"}
+	metaWS    = term{id: tWhitespace, name: "whitespace"}
+	metaError = &termError{term{id: tError, name: "error", typ: typeOfError}}
 )
 
 // TerminalFactory is a helper API to define a Terminal
@@ -45,6 +50,16 @@ func NewWhitespace() *TerminalFactory {
 	}
 }
 
+// Type declares type of values of the Terminal to check types of Do
+// handlers arguments by New. It's necessary for Func only, since other
+// factories know the type, unless `calc` returns an interface.
+//
+//	NewTerm(tIdent, "ident").Type(reflect.TypeOf("")).Func(matchIdent)
+func (t *TerminalFactory) Type(typ reflect.Type) *TerminalFactory {
+	t.typ = typ
+	return t
+}
+
 // Hide sets "is hidden" flag for further Terminal `IsHidden()` result.
 func (t *TerminalFactory) Hide() *TerminalFactory {
 	t.hide = true
@@ -65,7 +80,7 @@ func (t *TerminalFactory) Hide() *TerminalFactory {
 //	NewTerm(tPlus, "plus").Byte('+')
 func (t *TerminalFactory) Byte(b byte, more ...byte) Terminal {
 	return &termFixed{
-		term: t.term,
+		term: t.typed(typeOfBytes),
 		b:    append([]byte{b}, more...),
 	}
 }
@@ -80,7 +95,7 @@ func (t *TerminalFactory) Bytes(b []byte) Terminal {
 		panic(errors.Wrap(ErrDefine, "empty bytes slice"))
 	}
 	return &termFixed{
-		term: t.term,
+		term: t.typed(typeOfBytes),
 		b:    b,
 	}
 }
@@ -95,7 +110,7 @@ func (t *TerminalFactory) Str(s string) Terminal {
 		panic(errors.Wrap(ErrDefine, "empty string"))
 	}
 	return &termFixed{
-		term: t.term,
+		term: t.typed(typeOfString),
 		b:    []byte(s),
 		v:    toString,
 	}
@@ -126,9 +141,11 @@ func (t *TerminalFactory) Func(fn MatchFunc) Terminal {
 //	func isDigit(b byte) bool              { return b >= '0' && b <= '9' }
 //	func bytesToInt(b []byte) (int, error) { return strconv.Atoi(string(b)) }
 func (t *TerminalFactory) FuncByte(ok func(byte) bool, calc ...any) Terminal {
+	// calc is checked before its type is used
+	fn := newMatchFunc((*State).TakeBytesFunc, ok, calc...)
 	return &termCallback{
-		term: t.term,
-		fn:   newMatchFunc((*State).TakeBytesFunc, ok, calc...),
+		term: t.typed(matchValueType(typeOfBytes, calc)),
+		fn:   fn,
 	}
 }
 
@@ -145,10 +162,22 @@ func (t *TerminalFactory) FuncByte(ok func(byte) bool, calc ...any) Terminal {
 //
 //	NewWhitespace().FuncRune(unicode.IsSpace)
 func (t *TerminalFactory) FuncRune(ok func(rune) bool, calc ...any) Terminal {
+	// calc is checked before its type is used
+	fn := newMatchFunc((*State).TakeRunesFunc, ok, calc...)
 	return &termCallback{
-		term: t.term,
-		fn:   newMatchFunc((*State).TakeRunesFunc, ok, calc...),
+		term: t.typed(matchValueType(typeOfRunes, calc)),
+		fn:   fn,
+	}
+}
+
+// typed returns term with the given type of values unless other type is
+// declared by Type
+func (t *TerminalFactory) typed(typ reflect.Type) term {
+	res := t.term
+	if res.typ == nil {
+		res.typ = typ
 	}
+	return res
 }
 
 type termFixed struct {
@@ -188,12 +217,35 @@ type term struct {
 	id   Id
 	name string
 	hide bool
+	// typ is type of values if known
+	typ reflect.Type
 }
 
 func (m *term) Id() Id         { return m.id }
 func (m *term) Name() string   { return m.name }
 func (m *term) IsHidden() bool { return m.hide }
 
+func (m *term) valueType() reflect.Type { return m.typ }
+
+var (
+	typeOfBytes  = reflect.TypeOf([]byte(nil))
+	typeOfRunes  = reflect.TypeOf([]rune(nil))
+	typeOfString = reflect.TypeOf("")
+)
+
+// matchValueType returns type of values of a Terminal made by FuncByte or
+// FuncRune with the given `calc`, or nil when `calc` is not a func with result
+func matchValueType(slice reflect.Type, calc []any) reflect.Type {
+	if len(calc) == 0 {
+		return slice
+	}
+	typ := reflect.TypeOf(calc[0])
+	if typ == nil || typ.Kind() != reflect.Func || typ.NumOut() < 1 {
+		return nil
+	}
+	return typ.Out(0)
+}
+
 func toString(b []byte) any { return string(b) }
 
 // newMatchFunc is generic wrapper to craft a MatchFunc from `ok` and optional
@@ -285,3 +337,11 @@ func newValueFunc[T any](fn any) func(v []T) any {
 		))
 	}
 }
+
+// termError is Error pseudo-terminal. It's never matched from input, but
+// shifted by parser recovering from error.
+type termError struct {
+	term
+}
+
+func (*termError) Match(*State) (*State, any) { return nil, nil }
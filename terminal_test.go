@@ -2,6 +2,8 @@ package lr0
 
 import (
 	"testing"
+
+	"github.com/vovan-ve/go-lr0-parser/internal/testutils"
 )
 
 const tTemp Id = 17
@@ -99,3 +101,20 @@ func TestTerminalHide(t *testing.T) {
 		t.Error("a is not hidden")
 	}
 }
+
+func TestTermFunc_BadCalc(t *testing.T) {
+	for name, calc := range map[string]any{
+		"not func":   42,
+		"nil":        nil,
+		"no results": func([]byte) {},
+	} {
+		t.Run("byte: "+name, func(t *testing.T) {
+			defer testutils.ExpectPanicError(t, ErrDefine)
+			NewTerm(tTemp, "t").FuncByte(isDigit, calc)
+		})
+		t.Run("rune: "+name, func(t *testing.T) {
+			defer testutils.ExpectPanicError(t, ErrDefine)
+			NewTerm(tTemp, "t").FuncRune(func(r rune) bool { return r == 'x' }, calc)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package lr0
+
+// Node is a node of concrete syntax tree returned by Parse with WithTree
+// option
+//
+// Nodes of EBNF symbols like ZeroOrMore are not created, their children are
+// added to the parent node instead.
+type Node struct {
+	// Id of the symbol
+	Id Id
+	// Name of the symbol
+	Name string
+	// Children are nodes of non-terminal definition. Hidden Terminals are
+	// omitted unless WithHiddenNodes option is used.
+	Children []*Node
+	// Bytes are matched from source. For non-terminal it includes
+	// whitespaces between children.
+	Bytes []byte
+	// Start and End are offsets of Bytes in source
+	Start, End int
+}
+
+// newLeafNode creates a Node for Terminal matched in source from start to
+// end
+func newLeafNode(id Id, reg SymbolRegistry, start, end *State) *Node {
+	n := &Node{Id: id, Name: reg.SymbolName(id)}
+	n.setSource(start, end)
+	return n
+}
+
+// newRuleNode creates a Node for the Rule reduced from the given stack items
+func newRuleNode(r Rule, items []stackItem, g *grammar, withHidden bool, start, end *State) *Node {
+	n := &Node{Id: r.Subject(), Name: g.SymbolName(r.Subject())}
+	n.setSource(start, end)
+	for i, it := range items {
+		// rules of EBNF symbols don't hide anything
+		if (r.IsHidden(i) || g.IsHidden(it.node)) && !withHidden {
+			continue
+		}
+		child, ok := it.value.(*Node)
+		if !ok {
+			continue
+		}
+		if _, ok := g.getEbnfSymbol(child.Id); ok {
+			n.Children = append(n.Children, child.Children...)
+			continue
+		}
+		n.Children = append(n.Children, child)
+	}
+	return n
+}
+
+func (n *Node) setSource(start, end *State) {
+	if start == nil || end == nil {
+		return
+	}
+	n.Bytes = start.bytesToIfKept(end)
+	n.Start, n.End = start.Offset(), end.Offset()
+}
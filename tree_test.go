@@ -0,0 +1,67 @@
+package lr0
+
+import (
+	"strings"
+	"testing"
+	"unicode"
+)
+
+func TestWithTree(t *testing.T) {
+	terminals := []Terminal{
+		NewTerm(tInt, "int").FuncByte(isDigit, bytesToInt),
+		NewTerm(tPlus, `"+"`).Hide().Str("+"),
+		NewTerm(tMinus, `"-"`).Hide().Str("-"),
+		NewWhitespace().FuncRune(unicode.IsSpace),
+	}
+	rules := []NonTerminalDefinition{
+		NewNT(nGoal, "Goal").Main().Is(nSum),
+		NewNT(nSum, "Sum").
+			Is(nSum, tPlus, nVal).Do(calc2IntSum).
+			Is(nVal),
+		NewNT(nVal, "Val").Is(ZeroOrMore(tMinus), tInt).Do(func(_ []any, v int) int { return v }),
+	}
+
+	var dump func(n *Node) string
+	dump = func(n *Node) string {
+		s := n.Name
+		if len(n.Children) == 0 {
+			return s + "<" + string(n.Bytes) + ">"
+		}
+		list := make([]string, 0, len(n.Children))
+		for _, c := range n.Children {
+			list = append(list, dump(c))
+		}
+		return s + "(" + strings.Join(list, " ") + ")"
+	}
+
+	for _, c := range []struct {
+		name   string
+		opt    Option
+		expect string
+	}{
+		{"tree", WithTree(), `Sum(Sum(Val(int<1>)) Val(int<2>))`},
+		{"hidden", WithHiddenNodes(), `Sum(Sum(Val(int<1>)) "+"<+> Val("-"<-> "-"<-> int<2>))`},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			p := New(terminals, rules, c.opt)
+			v, err := p.Parse(NewState([]byte(" 1 + - -2 ")))
+			if err != nil {
+				t.Fatal(err)
+			}
+			n, ok := v.(*Node)
+			if !ok {
+				t.Fatalf("result is %#v", v)
+			}
+			if s := dump(n); s != c.expect {
+				t.Errorf("tree is %s", s)
+			}
+			if n.Id != nSum || string(n.Bytes) != "1 + - -2" || n.Start != 1 || n.End != 9 {
+				t.Errorf("root is %+v", n)
+			}
+			val := n.Children[len(n.Children)-1]
+			if val.Id != nVal || string(val.Bytes) != "- -2" || val.Start != 5 {
+				t.Errorf("Val is %+v", val)
+			}
+		})
+	}
+}